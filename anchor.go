@@ -0,0 +1,86 @@
+package gonp
+
+import (
+	"context"
+	"fmt"
+)
+
+// AnchorPair is a region known to match between a and b.
+// A and B are the positions of the region in a and b, and Len is the length of it
+type AnchorPair struct {
+	A, B, Len int
+}
+
+// NewAnchored is initializer of Diff which aligns each region of anchors between a and b.
+// Parts between anchors are diffed independently. anchors must be sorted, must not overlap
+// and each region must be equal between a and b
+func NewAnchored(a, b string, anchors []AnchorPair) (*Diff, error) {
+	diff := New(a, b)
+	ra, rb := []rune(a), []rune(b)
+	x, y := 0, 0
+	internal := make([]AnchorPair, len(anchors))
+	for i, anchor := range anchors {
+		if anchor.Len < 0 || anchor.A < x || anchor.B < y || anchor.A+anchor.Len > len(ra) || anchor.B+anchor.Len > len(rb) {
+			return nil, fmt.Errorf("gonp: anchor %d is out of order or range", i)
+		}
+		for j := 0; j < anchor.Len; j++ {
+			if ra[anchor.A+j] != rb[anchor.B+j] {
+				return nil, fmt.Errorf("gonp: anchor %d does not match at %d", i, j)
+			}
+		}
+		x, y = anchor.A+anchor.Len, anchor.B+anchor.Len
+		internal[i] = anchor
+		if diff.reverse {
+			internal[i].A, internal[i].B = anchor.B, anchor.A
+		}
+	}
+	diff.anchors = internal
+	return diff, nil
+}
+
+// findAnchoredPath searches the path of each part between anchors independently
+// and concatenates them through the anchors
+func (diff *DiffOf[T]) findAnchoredPath(ctx context.Context) ([]Point, error) {
+	points := make([]Point, 0)
+	ed := 0
+	x, y := 0, 0
+	for i := 0; i <= len(diff.anchors); i++ {
+		x1, y1, length := diff.m, diff.n, 0
+		if i < len(diff.anchors) {
+			x1, y1, length = diff.anchors[i].A, diff.anchors[i].B, diff.anchors[i].Len
+		}
+		sub := diff.subDiff(x, x1, y, y1)
+		sub.onlyEd = diff.onlyEd
+		sub.linearSpace = diff.linearSpace
+		sub.blank, sub.same = diff.blank, diff.same
+		if diff.maxEd >= 0 {
+			sub.maxEd = diff.maxEd - ed
+		}
+		epc, err := sub.findPath(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if sub.ed < 0 {
+			diff.ed = -1
+			return nil, nil
+		}
+		ed += sub.ed
+		for j := len(epc) - 1; j >= 0; j-- {
+			points = append(points, Point{X: epc[j].X + x, Y: epc[j].Y + y})
+		}
+		x, y = x1+length, y1+length
+		if i < len(diff.anchors) {
+			points = append(points, Point{X: x, Y: y})
+		}
+	}
+	diff.ed = ed
+	if diff.onlyEd {
+		return nil, nil
+	}
+
+	epc := make([]Point, len(points))
+	for i, p := range points {
+		epc[len(points)-1-i] = p
+	}
+	return epc, nil
+}
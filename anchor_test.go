@@ -0,0 +1,40 @@
+package gonp
+
+import (
+	"testing"
+)
+
+func TestDiffAnchored(t *testing.T) {
+	a, b := "xaby:cd", "abyx:dc"
+	diff, err := NewAnchored(a, b, []AnchorPair{{A: 4, B: 4, Len: 1}})
+	assert(t, err == nil)
+	diff.Compose()
+	assert(t, diff.Editdistance() == 4)
+	for _, e := range diff.Ses() {
+		if e.V == ':' {
+			assert(t, e.T == SesCommon && e.AIndex == 4 && e.BIndex == 4)
+		}
+	}
+	s, err := ApplySes(a, diff.Ses())
+	assert(t, err == nil && s == b)
+
+	diff, err = NewAnchored(a, "ab:dc", []AnchorPair{{A: 4, B: 2, Len: 1}})
+	assert(t, err == nil)
+	assert(t, diff.Editdistance() == 4)
+	s, err = ApplySes(a, diff.Ses())
+	assert(t, err == nil && s == "ab:dc")
+
+	diff, err = NewAnchored(a, b, []AnchorPair{{A: 4, B: 4, Len: 1}})
+	assert(t, err == nil)
+	diff.OnlyEd()
+	assert(t, diff.Editdistance() == 4)
+}
+
+func TestDiffAnchoredInvalid(t *testing.T) {
+	_, err := NewAnchored("abc", "abc", []AnchorPair{{A: 0, B: 1, Len: 1}})
+	assert(t, err != nil)
+	_, err = NewAnchored("abc", "abc", []AnchorPair{{A: 1, B: 1, Len: 1}, {A: 0, B: 0, Len: 1}})
+	assert(t, err != nil)
+	_, err = NewAnchored("abc", "abc", []AnchorPair{{A: 2, B: 2, Len: 2}})
+	assert(t, err != nil)
+}
@@ -0,0 +1,101 @@
+package gonp
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ApplySes reconstructs b from a and SES between a and b
+func ApplySes(a string, ses []SesElem) (string, error) {
+	var buf strings.Builder
+	if err := applySes(a, ses, &buf); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// CanApply reports whether ApplySes succeeds with a and ses without reconstructing b.
+// It returns the error describing the first position where ses does not match a
+func CanApply(a string, ses []SesElem) error {
+	return applySes(a, ses, nil)
+}
+
+// applySes applies ses to a writing the result to buf unless buf is nil
+func applySes(a string, ses []SesElem, buf *strings.Builder) error {
+	src := []rune(a)
+	i := 0
+	for _, e := range ses {
+		switch e.T {
+		case SesDelete, SesCommon:
+			if i >= len(src) {
+				return fmt.Errorf("gonp: SES exceeds a at position %d", i)
+			}
+			if src[i] != e.V {
+				return fmt.Errorf("gonp: SES element %q does not match %q at position %d", e.V, src[i], i)
+			}
+			if e.T == SesCommon && buf != nil {
+				buf.WriteRune(e.V)
+			}
+			i++
+		case SesAdd:
+			if buf != nil {
+				buf.WriteRune(e.V)
+			}
+		}
+	}
+	if i != len(src) {
+		return fmt.Errorf("gonp: SES ends before a at position %d", i)
+	}
+	return nil
+}
+
+// InvertSes returns SES from b to a by swapping additions and deletions of SES from a to b
+func InvertSes[T any](ses []SesElemOf[T]) []SesElemOf[T] {
+	inverted := make([]SesElemOf[T], len(ses))
+	for i, e := range ses {
+		switch e.T {
+		case SesDelete:
+			e.T = SesAdd
+		case SesAdd:
+			e.T = SesDelete
+		}
+		e.AIndex, e.BIndex = e.BIndex, e.AIndex
+		inverted[i] = e
+	}
+	return inverted
+}
+
+// SesEqual reports whether SES a and b are equivalent, that is, they have the same elements of the same types.
+// Indices are not compared, and deletions and additions between the same common elements
+// are compared regardless of the order of deletions and additions,
+// like SES composed with and without PreferDeletesFirst
+func SesEqual[T comparable](a, b []SesElemOf[T]) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a, b = normalizeSes(a), normalizeSes(b)
+	for i := range a {
+		if a[i].T != b[i].T || a[i].V != b[i].V {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeSes returns a copy of ses where deletions precede additions in each run of changes
+func normalizeSes[T any](ses []SesElemOf[T]) []SesElemOf[T] {
+	normalized := make([]SesElemOf[T], 0, len(ses))
+	adds := make([]SesElemOf[T], 0)
+	for _, e := range ses {
+		switch e.T {
+		case SesAdd:
+			adds = append(adds, e)
+		case SesDelete:
+			normalized = append(normalized, e)
+		default:
+			normalized = append(append(normalized, adds...), e)
+			adds = adds[:0]
+		}
+	}
+	return append(normalized, adds...)
+}
@@ -0,0 +1,85 @@
+package gonp
+
+import (
+	"testing"
+)
+
+func TestApplySes(t *testing.T) {
+	for _, c := range []struct{ a, b string }{
+		{"abc", "abd"},
+		{"abcdef", "dacfea"},
+		{"", "b"},
+		{"a", ""},
+		{"久保竜彦", "久保達彦"},
+	} {
+		diff := New(c.a, c.b)
+		diff.Compose()
+		b, err := ApplySes(c.a, diff.Ses())
+		assert(t, err == nil)
+		assert(t, b == c.b)
+	}
+}
+
+func TestApplySesMismatch(t *testing.T) {
+	diff := New("abc", "abd")
+	diff.Compose()
+	_, err := ApplySes("axc", diff.Ses())
+	assert(t, err != nil)
+	_, err = ApplySes("ab", diff.Ses())
+	assert(t, err != nil)
+	_, err = ApplySes("abcd", diff.Ses())
+	assert(t, err != nil)
+}
+
+func TestCanApply(t *testing.T) {
+	diff := New("abc", "abd")
+	diff.Compose()
+	assert(t, CanApply("abc", diff.Ses()) == nil)
+	for _, c := range []struct{ a, msg string }{
+		{"axc", "gonp: SES element 'b' does not match 'x' at position 1"},
+		{"ab", "gonp: SES exceeds a at position 2"},
+		{"abcd", "gonp: SES ends before a at position 3"},
+	} {
+		err := CanApply(c.a, diff.Ses())
+		assert(t, err != nil && err.Error() == c.msg)
+		_, applyErr := ApplySes(c.a, diff.Ses())
+		assert(t, applyErr.Error() == err.Error())
+	}
+}
+
+func TestInvertSes(t *testing.T) {
+	for _, c := range []struct{ a, b string }{
+		{"abc", "abd"},
+		{"acbdeacbed", "acebdabbabed"},
+		{"", "b"},
+	} {
+		diff := New(c.a, c.b)
+		inverted := InvertSes(diff.Ses())
+		a, err := ApplySes(c.b, inverted)
+		assert(t, err == nil)
+		assert(t, a == c.a)
+		assert(t, equalsSesElemOfArray(InvertSes(inverted), diff.Ses()))
+	}
+}
+
+func TestSesEqual(t *testing.T) {
+	for _, c := range []struct{ a, b string }{
+		{"abc", "abd"},
+		{"abcdef", "dacfea"},
+		{"acbdeacbed", "acebdabbabed"},
+		{"a\nb\nc\n", "a\nx\ny\nc\n"},
+	} {
+		diff := New(c.a, c.b)
+		deletesFirst := New(c.a, c.b)
+		deletesFirst.PreferDeletesFirst()
+		assert(t, SesEqual(diff.Ses(), deletesFirst.Ses()))
+		assert(t, SesEqual(diff.Ses(), diff.Ses()))
+	}
+
+	ses := []SesElem{{V: 'a', T: SesCommon}, {V: 'b', T: SesAdd}, {V: 'c', T: SesDelete}}
+	assert(t, SesEqual(ses, []SesElem{{V: 'a', T: SesCommon}, {V: 'c', T: SesDelete}, {V: 'b', T: SesAdd}}))
+	assert(t, !SesEqual(ses, []SesElem{{V: 'a', T: SesCommon}, {V: 'b', T: SesDelete}, {V: 'c', T: SesAdd}}))
+	assert(t, !SesEqual(ses, []SesElem{{V: 'c', T: SesDelete}, {V: 'a', T: SesCommon}, {V: 'b', T: SesAdd}}))
+	assert(t, !SesEqual(ses, ses[:2]))
+	assert(t, SesEqual([]SesElem{}, nil))
+}
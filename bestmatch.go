@@ -0,0 +1,42 @@
+package gonp
+
+import (
+	"math"
+)
+
+// BestMatch returns the index of the candidate most similar to target by Ratio and the ratio.
+// The first one wins among candidates with the same ratio, and index is -1 when candidates are empty.
+// Candidates which cannot beat the best one so far are pruned by their lengths before composing,
+// and the rest are composed with MaxEd bounded by the best ratio so far
+func BestMatch(target string, candidates []string) (index int, ratio float64) {
+	index, ratio = -1, 0.0
+	t := []rune(target)
+	for i, candidate := range candidates {
+		c := []rune(candidate)
+		total := len(t) + len(c)
+		if total == 0 {
+			if index == -1 || ratio < 1.0 {
+				index, ratio = i, 1.0
+			}
+			continue
+		}
+		// edit distance is at least the difference of lengths
+		if index != -1 && float64(2*min(len(t), len(c)))/float64(total) <= ratio {
+			continue
+		}
+		diff := NewRunes(t, c)
+		diff.OnlyEd()
+		if index != -1 {
+			// ratio is greater than the best one only when edit distance is less than this
+			diff.MaxEd(int(math.Ceil(float64(total)*(1-ratio))) - 1)
+		}
+		ed := diff.Editdistance()
+		if ed < 0 {
+			continue
+		}
+		if r := float64(total-ed) / float64(total); index == -1 || r > ratio {
+			index, ratio = i, r
+		}
+	}
+	return index, ratio
+}
@@ -0,0 +1,44 @@
+package gonp
+
+import (
+	"testing"
+)
+
+func TestBestMatch(t *testing.T) {
+	candidates := []string{"apple", "ape", "apply", "maple", "applet"}
+	index, ratio := BestMatch("appel", candidates)
+	assert(t, index == 0)
+	assert(t, ratio == New("appel", "apple").Ratio())
+
+	index, ratio = BestMatch("mapel", candidates)
+	assert(t, index == 3)
+	assert(t, ratio == 0.8)
+
+	// the first one wins among the same ratio
+	index, _ = BestMatch("abc", []string{"abx", "aby", "abc", "abc"})
+	assert(t, index == 2)
+
+	index, ratio = BestMatch("abc", nil)
+	assert(t, index == -1 && ratio == 0)
+
+	index, ratio = BestMatch("", []string{"a", ""})
+	assert(t, index == 1 && ratio == 1)
+
+	index, ratio = BestMatch("abc", []string{"xyz"})
+	assert(t, index == 0 && ratio == 0)
+}
+
+func TestBestMatchSameAsExhaustive(t *testing.T) {
+	candidates := []string{"kitten", "sitting", "mitten", "bitten", "knitting", "sit", "", "kitchen"}
+	for _, target := range []string{"sitten", "kit", "", "smitten", "knit"} {
+		best, bestRatio := -1, 0.0
+		for i, c := range candidates {
+			if r := New(target, c).Ratio(); best == -1 || r > bestRatio {
+				best, bestRatio = i, r
+			}
+		}
+		index, ratio := BestMatch(target, candidates)
+		assert(t, index == best)
+		assert(t, ratio == bestRatio)
+	}
+}
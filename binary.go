@@ -0,0 +1,166 @@
+package gonp
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// binaryVersion is the first byte of patch encoded by EncodeBinary
+const binaryVersion = 1
+
+// maxRunLength bounds the number of elements in a run so that it fits in int on any platform
+const maxRunLength = 1<<31 - 1
+
+// maxDecodedElems bounds the number of deleted and common elements decoded by DecodeBinary,
+// which are not backed by bytes of patch
+const maxDecodedElems = 1 << 24
+
+// EncodeBinary returns compact encoding of SES between a and b.
+// SES is encoded as runs of elements of the same manipulaton type.
+// Each run consists of uvarints of the type and the number of elements,
+// followed by UTF-8 encoding of the elements only when they are added
+func (diff *Diff) EncodeBinary() []byte {
+	buf := []byte{binaryVersion}
+	ses := diff.Ses()
+	for i := 0; i < len(ses); {
+		j := i
+		for j < len(ses) && ses[j].T == ses[i].T {
+			j++
+		}
+		buf = appendUvarint(buf, uint64(ses[i].T))
+		buf = appendUvarint(buf, uint64(j-i))
+		if ses[i].T == SesAdd {
+			for _, e := range ses[i:j] {
+				buf = utf8.AppendRune(buf, e.V)
+			}
+		}
+		i = j
+	}
+	return buf
+}
+
+// appendUvarint appends uvarint encoding of x to buf like binary.AppendUvarint, which needs Go 1.19
+func appendUvarint(buf []byte, x uint64) []byte {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(tmp[:], x)
+	return append(buf, tmp[:n]...)
+}
+
+// binaryRun is a run of elements of the same manipulaton type in binary patch.
+// added is the added characters when t is SesAdd
+type binaryRun struct {
+	t     SesType
+	count int
+	added []rune
+}
+
+// decodeRuns decodes runs from patch encoded by EncodeBinary.
+// Patch is rejected once deleted and common elements exceed limit,
+// so that untrusted patch can not make the caller allocate more than limit elements
+func decodeRuns(patch []byte, limit int) ([]binaryRun, error) {
+	if len(patch) == 0 {
+		return nil, ErrEmptyInput
+	}
+	if patch[0] != binaryVersion {
+		return nil, fmt.Errorf("gonp: unknown binary patch version")
+	}
+	runs := make([]binaryRun, 0)
+	total := 0
+	for pos := 1; pos < len(patch); {
+		t, n := binary.Uvarint(patch[pos:])
+		if n <= 0 {
+			return nil, fmt.Errorf("gonp: malformed binary patch at offset %d", pos)
+		}
+		if t > uint64(SesAdd) {
+			return nil, fmt.Errorf("gonp: unknown SesType %d in binary patch", t)
+		}
+		pos += n
+		count, n := binary.Uvarint(patch[pos:])
+		if n <= 0 || count > uint64(len(patch)) && SesType(t) == SesAdd || count > maxRunLength {
+			return nil, fmt.Errorf("gonp: malformed binary patch at offset %d", pos)
+		}
+		pos += n
+		run := binaryRun{t: SesType(t), count: int(count)}
+		switch run.t {
+		case SesDelete, SesCommon:
+			if run.count > limit-total {
+				return nil, fmt.Errorf("gonp: binary patch exceeds %d elements at offset %d", limit, pos)
+			}
+			total += run.count
+		case SesAdd:
+			run.added = make([]rune, 0, run.count)
+			for i := 0; i < run.count; i++ {
+				r, size := utf8.DecodeRune(patch[pos:])
+				if size == 0 || r == utf8.RuneError && size == 1 {
+					return nil, fmt.Errorf("gonp: malformed binary patch at offset %d", pos)
+				}
+				run.added = append(run.added, r)
+				pos += size
+			}
+		}
+		runs = append(runs, run)
+	}
+	return runs, nil
+}
+
+// DecodeBinary decodes SES from patch encoded by EncodeBinary.
+// Since patch carries only added characters, V of deleted and common elements is 0,
+// so they must be filled from a by AIndex to pass SES to ApplySes. Use ApplyBinary to reconstruct b.
+// Patch having more than 1<<24 deleted and common elements is rejected before they are allocated
+func DecodeBinary(patch []byte) ([]SesElem, error) {
+	runs, err := decodeRuns(patch, maxDecodedElems)
+	if err != nil {
+		return nil, err
+	}
+	ses := make([]SesElem, 0)
+	aIndex, bIndex := 0, 0
+	for _, run := range runs {
+		switch run.t {
+		case SesDelete:
+			for i := 0; i < run.count; i++ {
+				ses = append(ses, SesElem{T: SesDelete, AIndex: aIndex, BIndex: -1})
+				aIndex++
+			}
+		case SesCommon:
+			for i := 0; i < run.count; i++ {
+				ses = append(ses, SesElem{T: SesCommon, AIndex: aIndex, BIndex: bIndex})
+				aIndex++
+				bIndex++
+			}
+		case SesAdd:
+			for _, r := range run.added {
+				ses = append(ses, SesElem{V: r, T: SesAdd, AIndex: -1, BIndex: bIndex})
+				bIndex++
+			}
+		}
+	}
+	return ses, nil
+}
+
+// ApplyBinary reconstructs b from a and patch encoded by EncodeBinary
+func ApplyBinary(a string, patch []byte) (string, error) {
+	src := []rune(a)
+	runs, err := decodeRuns(patch, len(src))
+	if err != nil {
+		return "", err
+	}
+	var buf strings.Builder
+	i := 0
+	for _, run := range runs {
+		switch run.t {
+		case SesDelete, SesCommon:
+			if run.t == SesCommon {
+				buf.WriteString(string(src[i : i+run.count]))
+			}
+			i += run.count
+		case SesAdd:
+			buf.WriteString(string(run.added))
+		}
+	}
+	if i != len(src) {
+		return "", fmt.Errorf("gonp: binary patch ends before a at position %d", i)
+	}
+	return buf.String(), nil
+}
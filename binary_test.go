@@ -0,0 +1,92 @@
+package gonp
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestEncodeBinary(t *testing.T) {
+	patch := New("abcdef", "abXYef").EncodeBinary()
+	// version, 2 common, 2 deleted, 2 added "XY", 2 common
+	assert(t, bytes.Equal(patch, []byte{1, 1, 2, 0, 2, 2, 2, 'X', 'Y', 1, 2}))
+	assert(t, bytes.Equal(New("", "").EncodeBinary(), []byte{1}))
+}
+
+func TestBinaryRoundTrip(t *testing.T) {
+	pairs := [][2]string{
+		{"abc", "abd"},
+		{"", "新しい"},
+		{"古い", ""},
+		{"acbdeacbed", "acebdabbabed"},
+		{"日本語のテキスト", "日本のテキスト!"},
+	}
+	for _, pair := range pairs {
+		diff := New(pair[0], pair[1])
+		patch := diff.EncodeBinary()
+		b, err := ApplyBinary(pair[0], patch)
+		assert(t, err == nil)
+		assert(t, b == pair[1])
+
+		ses, err := DecodeBinary(patch)
+		assert(t, err == nil)
+		expected := diff.Ses()
+		assert(t, len(ses) == len(expected))
+		for i := range ses {
+			assert(t, ses[i].T == expected[i].T)
+			assert(t, ses[i].AIndex == expected[i].AIndex && ses[i].BIndex == expected[i].BIndex)
+			assert(t, ses[i].T != SesAdd || ses[i].V == expected[i].V)
+		}
+	}
+}
+
+func TestApplyBinaryError(t *testing.T) {
+	patch := New("abc", "abd").EncodeBinary()
+	_, err := ApplyBinary("ab", patch)
+	assert(t, err != nil)
+	_, err = ApplyBinary("abcd", patch)
+	assert(t, err != nil)
+	_, err = ApplyBinary("abc", nil)
+	assert(t, err != nil)
+	_, err = ApplyBinary("abc", []byte{1, 2, 5, 'x'})
+	assert(t, err != nil)
+	_, err = DecodeBinary([]byte{1, 9, 1})
+	assert(t, err != nil)
+	_, err = DecodeBinary([]byte{1, 0, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01})
+	assert(t, err != nil)
+	// 1<<32+1 is not truncated into SesCommon
+	_, err = DecodeBinary([]byte{1, 0x81, 0x80, 0x80, 0x80, 0x10, 1})
+	assert(t, err != nil)
+}
+
+func TestBinaryHugeRun(t *testing.T) {
+	// runs of 2^31-1 deleted and common elements in a few bytes
+	for _, typ := range []byte{0, 1} {
+		patch := []byte{1, typ, 0xff, 0xff, 0xff, 0xff, 0x07}
+		_, err := DecodeBinary(patch)
+		assert(t, err != nil)
+		_, err = ApplyBinary("abc", patch)
+		assert(t, err != nil)
+	}
+	// the total of runs is bounded as well as each run
+	patch := []byte{1, 1, 2, 0, 2}
+	_, err := ApplyBinary("abc", patch)
+	assert(t, err != nil)
+	ses, err := DecodeBinary(patch)
+	assert(t, err == nil && len(ses) == 4)
+	half := appendUvarint(nil, maxDecodedElems/2+1)
+	patch = append(append(append(append([]byte{1, 1}, half...), 0), half...), 1, 0)
+	_, err = DecodeBinary(patch)
+	assert(t, err != nil)
+	// patch does not carry deleted and common characters
+	for _, e := range ses {
+		assert(t, e.T == SesAdd || e.V == 0)
+	}
+}
+
+func TestEmptyPatch(t *testing.T) {
+	_, err := DecodeBinary(nil)
+	assert(t, errors.Is(err, ErrEmptyInput))
+	_, err = ApplyBinary("abc", []byte{})
+	assert(t, errors.Is(err, ErrEmptyInput))
+}
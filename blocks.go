@@ -0,0 +1,55 @@
+package gonp
+
+import (
+	"context"
+)
+
+// Block is a run of common elements like difflib.SequenceMatcher.get_matching_blocks
+// in Python. a[A:A+Size] is equal to b[B:B+Size]
+type Block struct {
+	A, B, Size int
+}
+
+// MatchingBlocks returns maximal runs of common elements of a and b in order.
+// The last block is the sentinel Block{A: len(a), B: len(b), Size: 0} like difflib.
+// Blocks are derived from the path on edit graph without composing SES when Compose has not been called yet,
+// so memory for them is O(D) in addition to searching the path, which is O(M+N) with LinearSpace
+func (diff *DiffOf[T]) MatchingBlocks() []Block {
+	points := diff.points
+	if !diff.composed || diff.onlyEd {
+		onlyEd := diff.onlyEd
+		diff.onlyEd = false
+		epc, _ := diff.findPath(context.Background())
+		diff.onlyEd = onlyEd
+		points = make([]Point, len(epc))
+		for i, p := range epc {
+			points[len(epc)-1-i] = p
+		}
+	}
+
+	blocks := make([]Block, 0)
+	x, y := 0, 0
+	for _, p := range points {
+		// each point is reached by deletions or additions followed by a snake
+		if d := (p.Y - p.X) - (y - x); d > 0 {
+			y += d
+		} else {
+			x -= d
+		}
+		size := p.X - x
+		if size > 0 {
+			block := Block{A: x, B: y, Size: size}
+			if diff.reverse {
+				block.A, block.B = block.B, block.A
+			}
+			if n := len(blocks); n > 0 && blocks[n-1].A+blocks[n-1].Size == block.A && blocks[n-1].B+blocks[n-1].Size == block.B {
+				blocks[n-1].Size += size
+			} else {
+				blocks = append(blocks, block)
+			}
+		}
+		x, y = p.X, p.Y
+	}
+	m, n := diff.lengths()
+	return append(blocks, Block{A: m, B: n, Size: 0})
+}
@@ -0,0 +1,68 @@
+package gonp
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestMatchingBlocks(t *testing.T) {
+	diff := New("abxcd", "abcd")
+	assert(t, reflect.DeepEqual(diff.MatchingBlocks(), []Block{
+		{A: 0, B: 0, Size: 2},
+		{A: 3, B: 2, Size: 2},
+		{A: 5, B: 4, Size: 0},
+	}))
+
+	diff = New("qabxcd", "abycdf")
+	blocks := diff.MatchingBlocks()
+	a, b := []rune("qabxcd"), []rune("abycdf")
+	assert(t, len(blocks) == 3)
+	for _, block := range blocks {
+		assert(t, string(a[block.A:block.A+block.Size]) == string(b[block.B:block.B+block.Size]))
+	}
+	assert(t, blocks[len(blocks)-1] == Block{A: 6, B: 6, Size: 0})
+
+	assert(t, reflect.DeepEqual(New("", "").MatchingBlocks(), []Block{{A: 0, B: 0, Size: 0}}))
+	assert(t, reflect.DeepEqual(New("abc", "xyz").MatchingBlocks(), []Block{{A: 3, B: 3, Size: 0}}))
+}
+
+// matchingBlocksFromSes returns the same as MatchingBlocks derived from SES
+func matchingBlocksFromSes[T any](diff *DiffOf[T]) []Block {
+	blocks := make([]Block, 0)
+	for _, h := range diff.Hunks() {
+		if h.Type == SesCommon {
+			blocks = append(blocks, Block{A: h.AStart, B: h.BStart, Size: len(h.Elems)})
+		}
+	}
+	m, n := diff.lengths()
+	return append(blocks, Block{A: m, B: n, Size: 0})
+}
+
+func TestMatchingBlocksLinearSpace(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	random := func(n int) []rune {
+		rs := make([]rune, rnd.Intn(n))
+		for i := range rs {
+			rs[i] = rune('a' + rnd.Intn(4))
+		}
+		return rs
+	}
+	for i := 0; i < 300; i++ {
+		a, b := random(200), random(200)
+		expected := matchingBlocksFromSes(NewRunes(a, b).runeDiff)
+
+		diff := NewRunes(a, b)
+		diff.LinearSpace()
+		assert(t, reflect.DeepEqual(diff.MatchingBlocks(), expected))
+		assert(t, !diff.composed)
+
+		diff = NewRunes(a, b)
+		diff.Compose()
+		assert(t, reflect.DeepEqual(diff.MatchingBlocks(), expected))
+
+		diff = NewRunes(a, b)
+		diff.OnlyEd()
+		assert(t, reflect.DeepEqual(diff.MatchingBlocks(), expected))
+	}
+}
@@ -0,0 +1,71 @@
+package gonp
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+)
+
+type byteDiff = DiffOf[byte]
+
+// BytesDiff is context for calculating difference between a and b of bytes
+type BytesDiff struct {
+	*byteDiff
+}
+
+func equalByte(x, y byte) bool {
+	return x == y
+}
+
+// NewBytes is initializer of BytesDiff. a and b are compared byte by byte without UTF-8 decoding
+func NewBytes(a, b []byte) *BytesDiff {
+	diff := &BytesDiff{NewSlice(a, b, equalByte)}
+	diff.match = commonPrefix[byte]
+	return diff
+}
+
+// NewByteLines is initializer of DiffOf comparing each line of bytes of a and b as an element
+// without UTF-8 decoding, like lines split from files read as bytes. a and b are used without copying
+func NewByteLines(a, b [][]byte) *DiffOf[[]byte] {
+	return NewSlice(a, b, bytes.Equal)
+}
+
+// PrintSes prints shortest edit script between a and b
+func (diff *BytesDiff) PrintSes() {
+	diff.FprintSes(os.Stdout)
+}
+
+// SprintSes returns string about shortest edit script between a and b
+func (diff *BytesDiff) SprintSes() string {
+	var buf bytes.Buffer
+	diff.FprintSes(&buf)
+	return buf.String()
+}
+
+// FprintSes emit about shortest edit script between a and b to w.
+// Non-printable bytes are emitted as hexadecimal
+func (diff *BytesDiff) FprintSes(w io.Writer) error {
+	for _, e := range diff.Ses() {
+		var err error
+		switch e.T {
+		case SesDelete:
+			_, err = fmt.Fprintf(w, "- %s\n", formatByte(e.V))
+		case SesAdd:
+			_, err = fmt.Fprintf(w, "+ %s\n", formatByte(e.V))
+		case SesCommon:
+			_, err = fmt.Fprintf(w, "  %s\n", formatByte(e.V))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func formatByte(c byte) string {
+	if c < 0x20 || c > 0x7e {
+		return fmt.Sprintf("0x%02x", c)
+	}
+	return string(c)
+}
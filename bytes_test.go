@@ -0,0 +1,50 @@
+package gonp
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDiffBytes(t *testing.T) {
+	diff := NewBytes([]byte{'a', 0xff, 'c'}, []byte{'a', 0xfe, 'c'})
+	diff.Compose()
+	sesActual := diff.Ses()
+	sesExpected := []SesElemOf[byte]{
+		{V: 'a', T: SesCommon},
+		{V: 0xff, T: SesDelete},
+		{V: 0xfe, T: SesAdd},
+		{V: 'c', T: SesCommon},
+	}
+	assert(t, diff.Editdistance() == 2)
+	assert(t, bytes.Equal(diff.Lcs(), []byte("ac")))
+	assert(t, equalsSesElemOfArray(sesActual, sesExpected))
+}
+
+func TestDiffBytesSprintSes(t *testing.T) {
+	diff := NewBytes([]byte("a\nb"), []byte("a\x00b"))
+	diff.Compose()
+	ses := diff.SprintSes()
+	assert(t, ses == "  a\n- 0x0a\n+ 0x00\n  b\n")
+}
+
+func TestDiffByteLines(t *testing.T) {
+	a := [][]byte{[]byte("a\n"), []byte("\xff\n"), []byte("c\n")}
+	b := [][]byte{[]byte("a\n"), []byte("\xfe\n"), []byte("c\n")}
+	diff := NewByteLines(a, b)
+	diff.Compose()
+	expected := []struct {
+		v string
+		t SesType
+	}{
+		{"a\n", SesCommon},
+		{"\xff\n", SesDelete},
+		{"\xfe\n", SesAdd},
+		{"c\n", SesCommon},
+	}
+	ses := diff.Ses()
+	assert(t, diff.Editdistance() == 2)
+	assert(t, len(ses) == len(expected))
+	for i, e := range expected {
+		assert(t, string(ses[i].V) == e.v && ses[i].T == e.t)
+	}
+}
@@ -0,0 +1,71 @@
+package gonp
+
+import (
+	"unicode"
+)
+
+const (
+	codeClassOther = iota
+	codeClassLower
+	codeClassUpper
+	codeClassDigit
+	codeClassUnderscore
+	codeClassSpace
+)
+
+func codeClass(r rune) int {
+	switch {
+	case unicode.IsUpper(r):
+		return codeClassUpper
+	case unicode.IsLetter(r):
+		return codeClassLower
+	case unicode.IsDigit(r):
+		return codeClassDigit
+	case r == '_':
+		return codeClassUnderscore
+	case unicode.IsSpace(r):
+		return codeClassSpace
+	}
+	return codeClassOther
+}
+
+// splitCode splits s into sub-tokens of identifiers. Identifiers are split at transitions
+// from lowercase to uppercase letters, before the last uppercase letter of an acronym
+// followed by lowercase letters like "HTTPServer", between letters and digits, and
+// around runs of underscores. Runs of whitespace are single tokens, combining marks
+// belong to the preceding character and any other character is a token by itself
+func splitCode(s string) []string {
+	tokens := make([]string, 0)
+	type char struct {
+		pos   int
+		class int
+	}
+	chars := make([]char, 0, len(s))
+	for i, r := range s {
+		if unicode.IsMark(r) && len(chars) > 0 {
+			continue
+		}
+		chars = append(chars, char{pos: i, class: codeClass(r)})
+	}
+	start := 0
+	for i := 1; i < len(chars); i++ {
+		prev, cur := chars[i-1].class, chars[i].class
+		split := prev != cur
+		switch {
+		case prev == codeClassUpper && cur == codeClassLower:
+			split = false
+		case prev == codeClassUpper && cur == codeClassUpper:
+			split = i+1 < len(chars) && chars[i+1].class == codeClassLower
+		case prev == codeClassOther:
+			split = true
+		}
+		if split {
+			tokens = append(tokens, s[start:chars[i].pos])
+			start = chars[i].pos
+		}
+	}
+	if start < len(s) {
+		tokens = append(tokens, s[start:])
+	}
+	return tokens
+}
@@ -0,0 +1,40 @@
+package gonp
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSplitCode(t *testing.T) {
+	tests := []struct {
+		s      string
+		tokens []string
+	}{
+		{"", []string{}},
+		{"getUserName", []string{"get", "User", "Name"}},
+		{"HTTPServer", []string{"HTTP", "Server"}},
+		{"userID", []string{"user", "ID"}},
+		{"snake_case_name", []string{"snake", "_", "case", "_", "name"}},
+		{"__init__", []string{"__", "init", "__"}},
+		{"v2Beta", []string{"v", "2", "Beta"}},
+		{"x := a.b(c)", []string{"x", " ", ":", "=", " ", "a", ".", "b", "(", "c", ")"}},
+		{"caf\u00e9Au", []string{"caf\u00e9", "Au"}},
+		{"cafe\u0301Au", []string{"cafe\u0301", "Au"}},
+	}
+	for _, tt := range tests {
+		tokens := splitCode(tt.s)
+		assert(t, len(tokens) == len(tt.tokens))
+		for i := range tokens {
+			assert(t, tokens[i] == tt.tokens[i])
+		}
+		assert(t, strings.Join(tokens, "") == tt.s)
+	}
+}
+
+func TestCodeTokenizer(t *testing.T) {
+	diff := NewTokens("name := getUserName()", "name := getUserID()", CodeTokenizer)
+	assert(t, diff.Editdistance() == 2)
+	deleted, added := diff.OnlyDeletes(), diff.OnlyAdds()
+	assert(t, len(deleted) == 1 && deleted[0].V == "Name")
+	assert(t, len(added) == 1 && added[0].V == "ID")
+}
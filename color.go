@@ -0,0 +1,82 @@
+package gonp
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+var (
+	// ColorDelete is ANSI escape code for deleted elements
+	ColorDelete = "\x1b[31m"
+	// ColorAdd is ANSI escape code for added elements
+	ColorAdd = "\x1b[32m"
+	// ColorReset is ANSI escape code to reset color
+	ColorReset = "\x1b[0m"
+)
+
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+// PrintSesColor prints colored shortest edit script between a and b.
+// Colors are disabled when stdout is not a terminal
+func (diff *Diff) PrintSesColor() {
+	if !isTerminal(os.Stdout) {
+		diff.PrintSes()
+		return
+	}
+	diff.FprintSesColor(os.Stdout)
+}
+
+// FprintSesColor emit about colored shortest edit script between a and b to w
+func (diff *Diff) FprintSesColor(w io.Writer) error {
+	for _, e := range diff.Ses() {
+		var err error
+		switch e.T {
+		case SesDelete:
+			_, err = fmt.Fprintf(w, "%s- %s%s\n", ColorDelete, diff.formatRune(e.V), ColorReset)
+		case SesAdd:
+			_, err = fmt.Fprintf(w, "%s+ %s%s\n", ColorAdd, diff.formatRune(e.V), ColorReset)
+		case SesCommon:
+			_, err = fmt.Fprintf(w, "  %s\n", diff.formatRune(e.V))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PrintSesColor prints colored shortest edit script between a and b.
+// Colors are disabled when stdout is not a terminal
+func (diff *LineDiff) PrintSesColor() {
+	if !isTerminal(os.Stdout) {
+		diff.PrintSes()
+		return
+	}
+	diff.FprintSesColor(os.Stdout)
+}
+
+// FprintSesColor emit about colored shortest edit script between a and b to w
+func (diff *LineDiff) FprintSesColor(w io.Writer) error {
+	return diff.eachCollapsed(func(e SesElemOf[string]) error {
+		var err error
+		switch e.T {
+		case SesDelete:
+			_, err = fmt.Fprintf(w, "%s- %s%s\n", ColorDelete, trimLine(e.V), ColorReset)
+		case SesAdd:
+			_, err = fmt.Fprintf(w, "%s+ %s%s\n", ColorAdd, trimLine(e.V), ColorReset)
+		case SesCommon:
+			_, err = fmt.Fprintf(w, "  %s\n", trimLine(e.V))
+		}
+		return err
+	}, func(n int) error {
+		_, err := fmt.Fprintf(w, collapsedFormat, n)
+		return err
+	})
+}
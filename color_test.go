@@ -0,0 +1,20 @@
+package gonp
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestFprintSesColor(t *testing.T) {
+	diff := New("abc", "abd")
+	var buf bytes.Buffer
+	diff.FprintSesColor(&buf)
+	assert(t, buf.String() == "  a\n  b\n\x1b[31m- c\x1b[0m\n\x1b[32m+ d\x1b[0m\n")
+}
+
+func TestFprintSesColorLines(t *testing.T) {
+	diff := NewLines("a\nb\n", "a\nc")
+	var buf bytes.Buffer
+	diff.FprintSesColor(&buf)
+	assert(t, buf.String() == "  a\n\x1b[31m- b\x1b[0m\n\x1b[32m+ c\x1b[0m\n")
+}
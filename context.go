@@ -0,0 +1,89 @@
+package gonp
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ContextFormat returns GNU diff's context format diff between a and b like diff -c.
+// n is the number of unchanged lines surrounding each change. Changed lines are marked with "! ",
+// deleted lines with "- " and added lines with "+ ", and the lines of a hunk in a or b are
+// omitted when they have no changes
+func (diff *LineDiff) ContextFormat(fromFile, toFile string, n int) string {
+	hunks := diff.lineHunks(n)
+	if len(hunks) == 0 {
+		return ""
+	}
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "*** %s\n", fromFile)
+	fmt.Fprintf(&buf, "--- %s\n", toFile)
+	for _, h := range hunks {
+		marks := contextMarks(h.ses)
+		buf.WriteString("***************\n")
+		fmt.Fprintf(&buf, "*** %s ****\n", contextRange(h.aStart, h.aLen))
+		writeContextLines(&buf, h.ses, marks, SesDelete)
+		fmt.Fprintf(&buf, "--- %s ----\n", contextRange(h.bStart, h.bLen))
+		writeContextLines(&buf, h.ses, marks, SesAdd)
+	}
+	return buf.String()
+}
+
+// contextMarks returns the mark of each element of ses.
+// Elements of a run of changes having both deletions and additions are marked with "!"
+func contextMarks(ses []SesElemOf[string]) []string {
+	marks := make([]string, len(ses))
+	for i := 0; i < len(ses); {
+		if ses[i].T == SesCommon {
+			marks[i] = " "
+			i++
+			continue
+		}
+		j := i
+		types := make(map[SesType]bool)
+		for ; j < len(ses) && ses[j].T != SesCommon; j++ {
+			types[ses[j].T] = true
+		}
+		for k := i; k < j; k++ {
+			switch {
+			case len(types) > 1:
+				marks[k] = "!"
+			case ses[k].T == SesDelete:
+				marks[k] = "-"
+			default:
+				marks[k] = "+"
+			}
+		}
+		i = j
+	}
+	return marks
+}
+
+// writeContextLines writes common lines and lines of t in ses if any of them is t
+func writeContextLines(buf *strings.Builder, ses []SesElemOf[string], marks []string, t SesType) {
+	changed := false
+	for _, e := range ses {
+		if e.T == t {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return
+	}
+	for i, e := range ses {
+		if e.T != SesCommon && e.T != t {
+			continue
+		}
+		buf.WriteString(marks[i] + " " + terminateLine(e.V))
+		if !strings.HasSuffix(e.V, "\n") {
+			buf.WriteString(noNewlineMarker)
+		}
+	}
+}
+
+func contextRange(start, length int) string {
+	if length <= 1 {
+		return fmt.Sprintf("%d", start+length-1)
+	}
+	return fmt.Sprintf("%d,%d", start, start+length-1)
+}
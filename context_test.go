@@ -0,0 +1,23 @@
+package gonp
+
+import (
+	"testing"
+)
+
+// expected outputs are generated by diff -C of GNU diffutils 3.8
+func TestContextFormat(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		n        int
+		expected string
+	}{
+		{"a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\n", "a\nB\nc\ne\nf\ng\nh\ni\nj\nk\nl\nm\nn\no", 1, "*** a.txt\n--- b.txt\n***************\n*** 1,5 ****\n  a\n! b\n  c\n- d\n  e\n--- 1,4 ----\n  a\n! B\n  c\n  e\n***************\n*** 13 ****\n--- 12,14 ----\n  m\n+ n\n+ o\n\\ No newline at end of file\n"},
+		{"p\nq\nr\ns\n", "p\nq\nX\nr\ns\n", 3, "*** a.txt\n--- b.txt\n***************\n*** 1,4 ****\n--- 1,5 ----\n  p\n  q\n+ X\n  r\n  s\n"},
+		{"x\n", "", 3, "*** a.txt\n--- b.txt\n***************\n*** 1 ****\n- x\n--- 0 ----\n"},
+		{"a\n", "a\n", 3, ""},
+	}
+	for _, tt := range tests {
+		diff := NewLines(tt.a, tt.b)
+		assert(t, diff.ContextFormat("a.txt", "b.txt", tt.n) == tt.expected)
+	}
+}
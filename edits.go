@@ -0,0 +1,89 @@
+package gonp
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// PositionEncoding is the unit in which columns of TextEdit are counted
+type PositionEncoding int
+
+const (
+	// PositionRunes counts columns in runes
+	PositionRunes PositionEncoding = iota
+	// PositionUTF8 counts columns in bytes of UTF-8
+	PositionUTF8
+	// PositionUTF16 counts columns in code units of UTF-16, which is the default of LSP.
+	// Characters outside the Basic Multilingual Plane like emoji are two code units
+	PositionUTF16
+)
+
+// width returns the number of units of r in enc.
+// Invalid runes are counted as utf8.RuneError, which they are encoded to
+func (enc PositionEncoding) width(r rune) int {
+	switch enc {
+	case PositionUTF8:
+		if n := utf8.RuneLen(r); n > 0 {
+			return n
+		}
+		return utf8.RuneLen(utf8.RuneError)
+	case PositionUTF16:
+		// characters outside the Basic Multilingual Plane are encoded as surrogate pairs
+		if r >= 0x10000 && r <= utf8.MaxRune {
+			return 2
+		}
+		return 1
+	}
+	return 1
+}
+
+// TextEdit is a replacement of range in a with NewText like LSP.
+// Lines and columns are zero-based, and columns are counted in runes unless specified by EditsIn.
+// The range is empty when NewText is inserted, and NewText is empty when the range is deleted
+type TextEdit struct {
+	StartLine, StartCol int
+	EndLine, EndCol     int
+	NewText             string
+}
+
+// Edits returns edits transforming a into b. Adjacent deletions and additions are coalesced into an edit.
+// Every range refers to positions in a, so edits can be applied in reverse order without adjusting them
+func (diff *Diff) Edits() []TextEdit {
+	return diff.EditsIn(PositionRunes)
+}
+
+// EditsIn returns edits transforming a into b like Edits, with columns counted in enc
+func (diff *Diff) EditsIn(enc PositionEncoding) []TextEdit {
+	edits := make([]TextEdit, 0)
+	line, col := 0, 0
+	advance := func(r rune) {
+		if r == '\n' {
+			line++
+			col = 0
+		} else {
+			col += enc.width(r)
+		}
+	}
+	ses := diff.Ses()
+	i := 0
+	for i < len(ses) {
+		if ses[i].T == SesCommon {
+			advance(ses[i].V)
+			i++
+			continue
+		}
+		edit := TextEdit{StartLine: line, StartCol: col}
+		var text strings.Builder
+		for ; i < len(ses) && ses[i].T != SesCommon; i++ {
+			if ses[i].T == SesDelete {
+				advance(ses[i].V)
+			} else {
+				text.WriteRune(ses[i].V)
+			}
+		}
+		edit.EndLine, edit.EndCol = line, col
+		edit.NewText = text.String()
+		edits = append(edits, edit)
+	}
+	return edits
+}
@@ -0,0 +1,79 @@
+package gonp
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEdits(t *testing.T) {
+	diff := New("abc\ndef\nghi", "abX\ndef\nhi!")
+	assert(t, reflect.DeepEqual(diff.Edits(), []TextEdit{
+		{StartLine: 0, StartCol: 2, EndLine: 0, EndCol: 3, NewText: "X"},
+		{StartLine: 2, StartCol: 0, EndLine: 2, EndCol: 1, NewText: ""},
+		{StartLine: 2, StartCol: 3, EndLine: 2, EndCol: 3, NewText: "!"},
+	}))
+
+	diff = New("ab\ncd", "a")
+	assert(t, reflect.DeepEqual(diff.Edits(), []TextEdit{
+		{StartLine: 0, StartCol: 1, EndLine: 1, EndCol: 2, NewText: ""},
+	}))
+
+	assert(t, len(New("same", "same").Edits()) == 0)
+}
+
+// applyEdits applies edits to s in reverse order
+func applyEdits(s string, edits []TextEdit) string {
+	lines := strings.SplitAfter(s, "\n")
+	offset := func(line, col int) int {
+		n := 0
+		for i := 0; i < line; i++ {
+			n += len([]rune(lines[i]))
+		}
+		return n + col
+	}
+	rs := []rune(s)
+	for i := len(edits) - 1; i >= 0; i-- {
+		e := edits[i]
+		start, end := offset(e.StartLine, e.StartCol), offset(e.EndLine, e.EndCol)
+		rs = append(rs[:start:start], append([]rune(e.NewText), rs[end:]...)...)
+	}
+	return string(rs)
+}
+
+func TestEditsApply(t *testing.T) {
+	pairs := [][2]string{
+		{"abc\ndef\nghi", "abX\ndef\nhi!"},
+		{"", "new\ntext"},
+		{"old\ntext\n", ""},
+		{"日本語\nテキスト", "日本\nのテキスト\n"},
+		{"acbdeacbed", "acebdabbabed"},
+	}
+	for _, pair := range pairs {
+		assert(t, applyEdits(pair[0], New(pair[0], pair[1]).Edits()) == pair[1])
+	}
+}
+
+func TestEditsIn(t *testing.T) {
+	// U+1F600 is 4 bytes in UTF-8 and a surrogate pair in UTF-16
+	a, b := "x\nあ\U0001F600b", "x\nあ\U0001F600c"
+	tests := []struct {
+		enc      PositionEncoding
+		startCol int
+	}{
+		{PositionRunes, 2},
+		{PositionUTF8, 7},
+		{PositionUTF16, 3},
+	}
+	for _, tt := range tests {
+		assert(t, reflect.DeepEqual(New(a, b).EditsIn(tt.enc), []TextEdit{
+			{StartLine: 1, StartCol: tt.startCol, EndLine: 1, EndCol: tt.startCol + 1, NewText: "c"},
+		}))
+	}
+	assert(t, reflect.DeepEqual(New(a, b).Edits(), New(a, b).EditsIn(PositionRunes)))
+
+	// invalid runes are counted as U+FFFD
+	diff := NewRunes([]rune{0xD800, 'a'}, []rune{0xD800, 'b'})
+	assert(t, diff.EditsIn(PositionUTF8)[0].StartCol == 3)
+	assert(t, diff.EditsIn(PositionUTF16)[0].StartCol == 1)
+}
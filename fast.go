@@ -0,0 +1,85 @@
+package gonp
+
+import (
+	"context"
+	"math"
+)
+
+const (
+	// fastPFactor is the multiple of sqrt(M+N) bounding p in each round of Fast mode
+	fastPFactor = 4
+	// fastMinP is the minimum bound of p in each round of Fast mode
+	fastMinP = 32
+)
+
+// Fast enables to compose approximately shortest SES quickly for large and quite different a and b.
+// Common prefix and suffix are trimmed first as usual, and only the rest is searched in Fast mode.
+// p of the main loop is bounded by max(32, 4*sqrt(M+N)). When the bound is reached before the end of
+// edit graph, the route to the farthest point reached so far is taken and the rest of edit graph
+// is searched again from there. SES is always valid and it is the shortest one as long as
+// the bound is not reached, but otherwise edit distance may be larger than the exact one.
+// It takes precedence over LinearSpace
+func (diff *DiffOf[T]) Fast() {
+	diff.fast = true
+}
+
+// findFastPath searches the path in rounds of bounded p, each of which starts from the end of the previous one
+func (diff *DiffOf[T]) findFastPath(ctx context.Context) ([]Point, error) {
+	maxP := max(fastMinP, int(fastPFactor*math.Sqrt(float64(diff.m+diff.n))))
+	forward := make([]Point, 0)
+	x, y := 0, 0
+	for {
+		sub := diff.subDiff(x, diff.m, y, diff.n)
+		diff.runIntoSuffix(sub, x, y, diff.tail)
+		sub.maxP = maxP
+		epc, err := sub.searchPath(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for i := len(epc) - 1; i >= 0; i-- {
+			forward = append(forward, Point{X: epc[i].X + x, Y: epc[i].Y + y})
+		}
+		if !sub.partial {
+			break
+		}
+		end := forward[len(forward)-1]
+		x, y = end.X, end.Y
+	}
+
+	diff.ed = 0
+	x, y = 0, 0
+	for _, p := range forward {
+		d := (p.Y - p.X) - (y - x)
+		diff.ed += max(d, -d)
+		x, y = p.X, p.Y
+	}
+	if diff.maxEd >= 0 && diff.ed > diff.maxEd {
+		diff.ed = -1
+		return nil, nil
+	}
+	if diff.onlyEd {
+		return nil, nil
+	}
+	epc := make([]Point, len(forward))
+	for i, p := range forward {
+		epc[len(forward)-1-i] = p
+	}
+	return epc, nil
+}
+
+// farthestRoute returns the route to the farthest point from the origin on fp in reverse order,
+// and marks the path as partial
+func (diff *DiffOf[T]) farthestRoute(fp []int, offset int) []Point {
+	diff.partial = true
+	best, bestK := -1, 0
+	for i, y := range fp {
+		k := i - offset
+		if y < 0 || y > diff.n || y-k < 0 || y-k > diff.m || diff.path[i] == -1 {
+			continue
+		}
+		if d := 2*y - k; d > best {
+			best, bestK = d, k
+		}
+	}
+	return diff.route(diff.path[bestK+offset])
+}
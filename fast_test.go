@@ -0,0 +1,69 @@
+package gonp
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func randomRunes(rnd *rand.Rand, n, alphabet int) []rune {
+	rs := make([]rune, n)
+	for i := range rs {
+		rs[i] = rune('a' + rnd.Intn(alphabet))
+	}
+	return rs
+}
+
+func TestDiffFast(t *testing.T) {
+	// the bound of p is never reached for similar inputs, so SES is the shortest one
+	diff := NewWith("acbdeacbed", "acebdabbabed", Fast())
+	exact := New("acbdeacbed", "acebdabbabed")
+	assert(t, diff.Editdistance() == exact.Editdistance())
+	assert(t, diff.SprintSes() == exact.SprintSes())
+
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 10; i++ {
+		a, b := randomRunes(rnd, 1000+rnd.Intn(1000), 4), randomRunes(rnd, 1000+rnd.Intn(1000), 4)
+		fast := NewRunes(a, b)
+		fast.Fast()
+		exact := NewRunes(a, b)
+		assert(t, fast.Editdistance() >= exact.Editdistance())
+		result, err := ApplySes(string(a), fast.Ses())
+		assert(t, err == nil && result == string(b))
+		added, deleted, _ := fast.Stats()
+		assert(t, added+deleted == fast.Editdistance())
+
+		onlyEd := NewRunes(a, b)
+		onlyEd.Fast()
+		onlyEd.OnlyEd()
+		assert(t, onlyEd.Editdistance() == fast.Editdistance())
+	}
+}
+
+func TestDiffFastTrimmed(t *testing.T) {
+	// common prefix is trimmed before searching in Fast mode
+	prefix := strings.Repeat("x", 5000)
+	diff := NewWith(prefix+"abc", prefix+"adc", Fast())
+	exact := New(prefix+"abc", prefix+"adc")
+	assert(t, diff.Editdistance() == 2)
+	assert(t, diff.SprintSes() == exact.SprintSes())
+	assert(t, diff.Work().SnakeSteps < len(prefix))
+}
+
+func BenchmarkDiffExact(b *testing.B) {
+	rnd := rand.New(rand.NewSource(1))
+	x, y := randomRunes(rnd, 20000, 4), randomRunes(rnd, 20000, 4)
+	for i := 0; i < b.N; i++ {
+		NewRunes(x, y).Compose()
+	}
+}
+
+func BenchmarkDiffFast(b *testing.B) {
+	rnd := rand.New(rand.NewSource(1))
+	x, y := randomRunes(rnd, 20000, 4), randomRunes(rnd, 20000, 4)
+	for i := 0; i < b.N; i++ {
+		diff := NewRunes(x, y)
+		diff.Fast()
+		diff.Compose()
+	}
+}
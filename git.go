@@ -0,0 +1,76 @@
+package gonp
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"fmt"
+	"strings"
+)
+
+const (
+	devNull     = "/dev/null"
+	gitNullHash = "0000000"
+)
+
+// GitPatch returns unified format diff between a and b with headers of git,
+// which can be applied by `git apply`. oldPath or newPath is "/dev/null"
+// when the file is created or deleted. When oldPath and newPath differ, the file is renamed,
+// and only the headers of the rename are written if the contents are the same
+func (diff *LineDiff) GitPatch(oldPath, newPath string) string {
+	a, b := diff.sequences()
+	textA, textB := strings.Join(a, ""), strings.Join(b, "")
+	hashA, hashB := gitBlobHash(textA), gitBlobHash(textB)
+
+	var buf bytes.Buffer
+	fromFile, toFile := "a/"+oldPath, "b/"+newPath
+	switch {
+	case oldPath == devNull:
+		fromFile = devNull
+		fmt.Fprintf(&buf, "diff --git a/%s b/%s\n", newPath, newPath)
+		fmt.Fprintf(&buf, "new file mode 100644\n")
+		fmt.Fprintf(&buf, "index %s..%s\n", gitNullHash, hashB)
+	case newPath == devNull:
+		toFile = devNull
+		fmt.Fprintf(&buf, "diff --git a/%s b/%s\n", oldPath, oldPath)
+		fmt.Fprintf(&buf, "deleted file mode 100644\n")
+		fmt.Fprintf(&buf, "index %s..%s\n", hashA, gitNullHash)
+	default:
+		if textA == textB && oldPath == newPath {
+			return ""
+		}
+		fmt.Fprintf(&buf, "diff --git a/%s b/%s\n", oldPath, newPath)
+		if oldPath != newPath {
+			fmt.Fprintf(&buf, "similarity index %d%%\n", diff.similarity(len(textA), len(textB)))
+			fmt.Fprintf(&buf, "rename from %s\n", oldPath)
+			fmt.Fprintf(&buf, "rename to %s\n", newPath)
+			if textA == textB {
+				return buf.String()
+			}
+		}
+		fmt.Fprintf(&buf, "index %s..%s 100644\n", hashA, hashB)
+	}
+	diff.fprintUnified(&buf, fromFile, toFile, 3)
+	return buf.String()
+}
+
+// similarity returns the percentage of bytes of common lines in the larger of a and b
+// of lenA and lenB bytes
+func (diff *LineDiff) similarity(lenA, lenB int) int {
+	size := max(lenA, lenB)
+	if size == 0 {
+		return 100
+	}
+	common := 0
+	for _, line := range diff.Lcs() {
+		common += len(line)
+	}
+	return common * 100 / size
+}
+
+// gitBlobHash returns abbreviated object name of s as a blob of git
+func gitBlobHash(s string) string {
+	h := sha1.New()
+	fmt.Fprintf(h, "blob %d\x00", len(s))
+	h.Write([]byte(s))
+	return fmt.Sprintf("%x", h.Sum(nil))[:7]
+}
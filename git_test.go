@@ -0,0 +1,74 @@
+package gonp
+
+import (
+	"testing"
+)
+
+func TestGitPatch(t *testing.T) {
+	diff := NewLines("a\nb\nc\n", "a\nB\nc\n")
+	expected := `diff --git a/foo.txt b/foo.txt
+index de98044..7be73ce 100644
+--- a/foo.txt
++++ b/foo.txt
+@@ -1,3 +1,3 @@
+ a
+-b
++B
+ c
+`
+	assert(t, diff.GitPatch("foo.txt", "foo.txt") == expected)
+	assert(t, NewLines("a\n", "a\n").GitPatch("foo.txt", "foo.txt") == "")
+}
+
+func TestGitPatchNewFile(t *testing.T) {
+	diff := NewLines("", "a\n")
+	expected := `diff --git a/foo.txt b/foo.txt
+new file mode 100644
+index 0000000..7898192
+--- /dev/null
++++ b/foo.txt
+@@ -0,0 +1 @@
++a
+`
+	assert(t, diff.GitPatch("/dev/null", "foo.txt") == expected)
+}
+
+func TestGitPatchDeletedFile(t *testing.T) {
+	diff := NewLines("a\n", "")
+	expected := `diff --git a/foo.txt b/foo.txt
+deleted file mode 100644
+index 7898192..0000000
+--- a/foo.txt
++++ /dev/null
+@@ -1 +0,0 @@
+-a
+`
+	assert(t, diff.GitPatch("foo.txt", "/dev/null") == expected)
+}
+
+func TestGitPatchRename(t *testing.T) {
+	diff := NewLines("a\nb\n", "a\nb\n")
+	expected := `diff --git a/foo.txt b/bar.txt
+similarity index 100%
+rename from foo.txt
+rename to bar.txt
+`
+	assert(t, diff.GitPatch("foo.txt", "bar.txt") == expected)
+
+	diff = NewLines("a\nb\nc\nd\n", "a\nb\nc\nD\n")
+	expected = `diff --git a/foo.txt b/bar.txt
+similarity index 75%
+rename from foo.txt
+rename to bar.txt
+index d68dd40..5790697 100644
+--- a/foo.txt
++++ b/bar.txt
+@@ -1,4 +1,4 @@
+ a
+ b
+ c
+-d
++D
+`
+	assert(t, diff.GitPatch("foo.txt", "bar.txt") == expected)
+}
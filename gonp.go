@@ -5,9 +5,14 @@ package gonp
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
-	"unicode/utf8"
+	"math"
+	"os"
+	"reflect"
+	"strconv"
+	"unicode"
 )
 
 const (
@@ -17,14 +22,35 @@ const (
 	SesCommon
 	// SesAdd is manipulaton type of adding element in SES
 	SesAdd
+	// SesMove is manipulaton type of moving element in SES
+	SesMove
+	// SesReplace is manipulaton type of replacing elements, which only appears in coalesced runs of SES
+	SesReplace
 )
 
 // SesType is manipulaton type
 type SesType int
 
+// String returns "Delete", "Common", "Add", "Move" or "Replace"
+func (t SesType) String() string {
+	switch t {
+	case SesDelete:
+		return "Delete"
+	case SesCommon:
+		return "Common"
+	case SesAdd:
+		return "Add"
+	case SesMove:
+		return "Move"
+	case SesReplace:
+		return "Replace"
+	}
+	return fmt.Sprintf("SesType(%d)", int(t))
+}
+
 // Point is coordinate in edit graph
 type Point struct {
-	x, y int
+	X, Y int
 }
 
 // PointWithRoute is coordinate in edit graph attached route
@@ -32,24 +58,90 @@ type PointWithRoute struct {
 	x, y, r int
 }
 
-// SesElem is element of SES
-type SesElem struct {
-	e rune
-	t SesType
+// SesElemOf is element of SES between sequences of T.
+// AIndex and BIndex are positions of V in a and b, or -1 when V is not in the sequence.
+// Key is given by the function set by SetKey, or empty
+type SesElemOf[T any] struct {
+	V      T       `json:"v"`
+	T      SesType `json:"t"`
+	AIndex int     `json:"a_index"`
+	BIndex int     `json:"b_index"`
+	Key    string  `json:"key,omitempty"`
 }
 
-// Diff is context for calculating difference between a and b
-type Diff struct {
-	a              []rune
-	b              []rune
+// String returns manipulaton type and quoted V like `Add 'a'`. V of other types than rune and string is formatted with %v
+func (e SesElemOf[T]) String() string {
+	switch v := any(e.V).(type) {
+	case rune, string:
+		return fmt.Sprintf("%s %q", e.T, v)
+	}
+	return fmt.Sprintf("%s %v", e.T, e.V)
+}
+
+// SesElem is element of SES between strings
+type SesElem = SesElemOf[rune]
+
+// DiffOf is context for calculating difference between a and b of any element type
+type DiffOf[T any] struct {
+	a              []T
+	b              []T
 	m, n           int
+	eq             func(x, y T) bool
 	ed             int
-	lcs            []rune
-	ses            []SesElem
+	lcs            []T
+	ses            []SesElemOf[T]
 	reverse        bool
 	path           []int
 	onlyEd         bool
+	composed       bool
+	limit          int
+	maxElems       int
+	maxEd          int
+	linearSpace    bool
+	cross          []Point
+	midY           int
+	mid            Point
+	anchors        []AnchorPair
+	points         []Point
+	truncated      bool
 	pointWithRoute []PointWithRoute
+	fp             []int
+	noSwap         bool
+	match          func(a, b []T) int
+	progress       func(p int)
+	recordFp       bool
+	alignSuffix    bool
+	deletesFirst   bool
+	key            func(v T) string
+	ignore         func(v T) bool
+	blank          func(v T) bool
+	same           func(x, y T) bool
+	work           *WorkStats
+	hash           func(v T) uint64
+	hashA, hashB   []uint64
+	fast           bool
+	maxP           int
+	partial        bool
+	tail           int
+	stepping       bool
+	stepP          int
+	stepSub        *DiffOf[T]
+	stepPrefix     int
+	stepSuffix     int
+	frontiers      [][]int
+}
+
+type runeDiff = DiffOf[rune]
+
+// Diff is context for calculating difference between a and b.
+// A Diff must not be used from multiple goroutines at the same time,
+// but separate Diffs may be composed concurrently since they share no state
+type Diff struct {
+	*runeDiff
+	ignoreCase       bool
+	ignoreWhitespace bool
+	bufA, bufB       []rune
+	escape           bool
 }
 
 func max(x, y int) int {
@@ -59,50 +151,566 @@ func max(x, y int) int {
 	return x
 }
 
-// New is initializer of Diff
+func min(x, y int) int {
+	if x > y {
+		return y
+	}
+	return x
+}
+
+func equalRune(x, y rune) bool {
+	return x == y
+}
+
+// chunkSize is the number of elements compared at a time by commonPrefix
+const chunkSize = 8
+
+// commonPrefix returns the length of common prefix of a and b.
+// Elements are compared chunkSize at a time by an unrolled loop, which is faster than
+// calling eq for each element when a and b share long runs
+func commonPrefix[T comparable](a, b []T) int {
+	n := min(len(a), len(b))
+	i := 0
+	for i+chunkSize <= n && equalChunk(a[i:i+chunkSize], b[i:i+chunkSize]) {
+		i += chunkSize
+	}
+	for i < n && a[i] == b[i] {
+		i++
+	}
+	return i
+}
+
+// equalChunk reports whether the first chunkSize elements of x and y are equal
+func equalChunk[T comparable](x, y []T) bool {
+	x, y = x[:chunkSize], y[:chunkSize]
+	return x[0] == y[0] && x[1] == y[1] && x[2] == y[2] && x[3] == y[3] &&
+		x[4] == y[4] && x[5] == y[5] && x[6] == y[6] && x[7] == y[7]
+}
+
+// New is initializer of Diff. It panics when the total number of characters of a and b exceeds MaxInputLength
 func New(a, b string) *Diff {
-	m, n := utf8.RuneCountInString(a), utf8.RuneCountInString(b)
-	diff := new(Diff)
-	diff.a, diff.b = []rune(a), []rune(b)
+	return NewWith(a, b)
+}
+
+// NewRunes is initializer of Diff for runes. a and b are used without copying
+func NewRunes(a, b []rune) *Diff {
+	diff := &Diff{runeDiff: NewSlice(a, b, equalRune)}
+	diff.match = commonPrefix[rune]
+	return diff
+}
+
+// IgnoreCase enables to compare characters case-insensitively.
+// SES and LCS still carry the original characters, and common characters are taken from a
+func (diff *Diff) IgnoreCase() {
+	diff.ignoreCase = true
+	diff.eq = diff.equal
+	diff.match = nil
+	diff.clearHash()
+}
+
+// IgnoreWhitespace enables to treat runs of whitespace characters in a line like spaces and tabs
+// as equal to each other like diff -w, collapsing each run into one to search the path.
+// SES still carries the original characters so that ApplySes reconstructs b:
+// identical characters at the beginning of matched runs are common, and the rest of them are
+// deleted and added, though they are not counted in edit distance
+func (diff *Diff) IgnoreWhitespace() {
+	diff.ignoreWhitespace = true
+	diff.blank = isBlank
+	diff.same = equalRune
+	diff.eq = diff.equal
+	diff.match = nil
+	diff.clearHash()
+}
+
+// EscapeControl enables to print non-printable characters like control characters
+// as Go escape sequences like \t, \n and \x1b, so output is safe for terminals and unambiguous
+func (diff *Diff) EscapeControl() {
+	diff.escape = true
+}
+
+func (diff *Diff) formatRune(r rune) string {
+	if diff.escape && !unicode.IsPrint(r) {
+		q := strconv.QuoteRune(r)
+		return q[1 : len(q)-1]
+	}
+	return string(r)
+}
+
+func (diff *Diff) equal(x, y rune) bool {
+	if diff.ignoreWhitespace && isBlank(x) && isBlank(y) {
+		return true
+	}
+	if diff.ignoreCase {
+		return unicode.ToLower(x) == unicode.ToLower(y)
+	}
+	return x == y
+}
+
+// equalerOf returns the comparison by Equal method of Equaler for elements implementing it,
+// falling back to eq for the others. Values of an interface type T are checked one by one
+func equalerOf[T any](eq func(x, y T) bool) func(x, y T) bool {
+	var zero T
+	if _, ok := any(zero).(Equaler[T]); ok {
+		return func(x, y T) bool {
+			return any(x).(Equaler[T]).Equal(y)
+		}
+	}
+	if reflect.TypeOf(&zero).Elem().Kind() != reflect.Interface {
+		if eq == nil {
+			panic(fmt.Sprintf("gonp: eq is nil and %T does not implement Equaler", zero))
+		}
+		return eq
+	}
+	return func(x, y T) bool {
+		if e, ok := any(x).(Equaler[T]); ok {
+			return e.Equal(y)
+		}
+		if eq == nil {
+			panic(fmt.Sprintf("gonp: eq is nil and %T does not implement Equaler", x))
+		}
+		return eq(x, y)
+	}
+}
+
+// MaxInputLength is the maximum total length of a and b.
+// Arrays indexed by diagonals of edit graph have m+n+3 elements, which must fit in int
+const MaxInputLength = math.MaxInt - 3
+
+// exceedsMaxInputLength reports whether m+n exceeds MaxInputLength without overflowing
+func exceedsMaxInputLength(m, n int) bool {
+	return m > MaxInputLength-n
+}
+
+// Equaler is implemented by elements which are compared with each other by NewSlice in preference to eq
+type Equaler[T any] interface {
+	Equal(other T) bool
+}
+
+// NewSlice is initializer of DiffOf. Elements of a and b implementing Equaler are compared
+// by its Equal method, and the others by eq. eq may be nil when T implements Equaler.
+// It panics when the total length of a and b exceeds MaxInputLength,
+// or eq is nil and an element to compare does not implement Equaler
+func NewSlice[T any](a, b []T, eq func(x, y T) bool) *DiffOf[T] {
+	if exceedsMaxInputLength(len(a), len(b)) {
+		panic("gonp: total length of a and b exceeds MaxInputLength")
+	}
+	diff := new(DiffOf[T])
+	diff.eq = equalerOf(eq)
+	diff.setSequences(a, b)
+	diff.onlyEd = false
+	diff.limit = -1
+	diff.maxElems = -1
+	diff.maxEd = -1
+	return diff
+}
+
+// NewInterface is initializer of DiffOf comparing elements of a and b of any types by eq like NewSlice.
+// It is for code which handles elements as interface{} without type parameters
+func NewInterface(a, b []interface{}, eq func(x, y interface{}) bool) *DiffOf[interface{}] {
+	return NewSlice(a, b, eq)
+}
+
+func (diff *DiffOf[T]) setSequences(a, b []T) {
+	m, n := len(a), len(b)
+	diff.a, diff.b = a, b
 	diff.m, diff.n = m, n
 	diff.reverse = false
-	if m >= n {
+	if m >= n && !diff.noSwap {
 		diff.a, diff.b = diff.b, diff.a
 		diff.m, diff.n = n, m
 		diff.reverse = true
 	}
-	diff.onlyEd = false
-	return diff
+	diff.rehash()
+}
+
+// Reset replaces a and b to compose diff between them again, reusing the buffers
+// allocated by the previous Compose. Settings such as eq, NoSwap, OnlyEd, Limit, MaxEd
+// and LinearSpace are retained, while anchors and the previous result are cleared.
+// SES, LCS and path returned before Reset must not be used after Compose is called again.
+// It panics when the total length of a and b exceeds MaxInputLength like NewSlice
+func (diff *DiffOf[T]) Reset(a, b []T) {
+	if exceedsMaxInputLength(len(a), len(b)) {
+		panic("gonp: total length of a and b exceeds MaxInputLength")
+	}
+	diff.setSequences(a, b)
+	diff.anchors = nil
+	diff.composed = false
+	diff.stepping = false
+	diff.ed = 0
+	diff.lcs = diff.lcs[:0]
+	diff.ses = diff.ses[:0]
+	diff.truncated = false
+}
+
+// Reset replaces a and b to compose diff between them again like DiffOf.Reset.
+// a and b are decoded into buffers owned by diff, which are reused on every Reset.
+// IgnoreCase and IgnoreWhitespace are retained
+func (diff *Diff) Reset(a, b string) {
+	diff.bufA = appendRunes(diff.bufA[:0], a)
+	diff.bufB = appendRunes(diff.bufB[:0], b)
+	diff.runeDiff.Reset(diff.bufA, diff.bufB)
+}
+
+func appendRunes(buf []rune, s string) []rune {
+	for _, r := range s {
+		buf = append(buf, r)
+	}
+	return buf
+}
+
+// resize returns s resliced to n elements, allocating only when its capacity is short
+func resize[E any](s []E, n int) []E {
+	if cap(s) < n {
+		return make([]E, n)
+	}
+	return s[:n]
+}
+
+// NoSwap disables to swap a and b internally when a is longer than b,
+// so the order of SES elements does not depend on the lengths of a and b.
+// Complexity is still O(NP), where P is the smaller one of the numbers of deletions and additions,
+// though it may be slightly slower since more diagonals are visited before reaching the end
+func (diff *DiffOf[T]) NoSwap() {
+	diff.noSwap = true
+	if diff.reverse {
+		diff.a, diff.b = diff.b, diff.a
+		diff.m, diff.n = diff.n, diff.m
+		diff.hashA, diff.hashB = diff.hashB, diff.hashA
+		diff.reverse = false
+		for i := range diff.anchors {
+			diff.anchors[i].A, diff.anchors[i].B = diff.anchors[i].B, diff.anchors[i].A
+		}
+	}
+}
+
+// OnProgress registers fn called once per iteration of the main loop of Compose with p,
+// the number of deletions or additions, whichever is smaller, ruled out so far.
+// Edit distance is at least |M-N|+2p, so p gives a rough progress signal for large diffs.
+// With LinearSpace or when common prefix or suffix is trimmed, p restarts from 0 for each part of the edit graph
+func (diff *DiffOf[T]) OnProgress(fn func(p int)) {
+	diff.progress = fn
+}
+
+// RecordFrontiers enables to record snapshots of fp, the frontier of the farthest points
+// on each diagonal, after every iteration of p in Compose for diagnostics.
+// Frontiers are recorded by searching the whole edit graph in the same way as the paper
+// without trimming common prefix and suffix nor LinearSpace, apart from finding the result,
+// so it is not changed
+func (diff *DiffOf[T]) RecordFrontiers() {
+	diff.recordFp = true
+}
+
+// Frontiers returns snapshots of fp recorded by RecordFrontiers in order of p.
+// Frontiers()[p][k+M+1] is the farthest Y on diagonal k = Y-X of edit graph after the iteration of p,
+// or -1 when diagonal k is not reached yet, where M is the length of X side of Path.
+// It returns nil when RecordFrontiers is not enabled, or anchors are given
+func (diff *DiffOf[T]) Frontiers() [][]int {
+	diff.composeIfNeeded()
+	return diff.frontiers
+}
+
+// PreferDeletesFirst makes deletions precede additions between common elements in SES
+// like GNU diff. Otherwise, the order depends on whether a and b are swapped internally
+func (diff *DiffOf[T]) PreferDeletesFirst() {
+	diff.deletesFirst = true
+}
+
+// Reversed reports whether a and b are swapped internally since a is longer than b.
+// Then X and Y of points returned by Path are positions in b and a respectively
+func (diff *DiffOf[T]) Reversed() bool {
+	return diff.reverse
 }
 
 // OnlyEd enables to calculate only edit distance
-func (diff *Diff) OnlyEd() {
+func (diff *DiffOf[T]) OnlyEd() {
 	diff.onlyEd = true
 }
 
-// Editdistance returns edit distance between a and b
-func (diff *Diff) Editdistance() int {
+// Limit limits SES to the first n elements which are not SesCommon.
+// Edit distance is still calculated exactly
+func (diff *DiffOf[T]) Limit(n int) {
+	diff.limit = n
+}
+
+// MaxScriptElements limits SES to the first n elements including SesCommon.
+// SES is cut after the last common element within n elements if any,
+// so a run of changes between common elements is not split. Edit distance is still calculated exactly
+func (diff *DiffOf[T]) MaxScriptElements(n int) {
+	diff.maxElems = n
+}
+
+// MaxEd makes Compose give up when edit distance exceeds limit.
+// Then Editdistance returns -1 and SES and LCS are empty
+func (diff *DiffOf[T]) MaxEd(limit int) {
+	diff.maxEd = limit
+}
+
+// SetEqual makes elements of a and b compared by fn. SES and LCS still carry the original elements,
+// and common elements are taken from a. For Diff, fn replaces the comparison enabled by
+// IgnoreCase and IgnoreWhitespace
+func (diff *DiffOf[T]) SetEqual(fn func(x, y T) bool) {
+	diff.eq = fn
+	diff.match = nil
+	diff.clearHash()
+}
+
+// SetKey makes each element of SES carry fn(V) as Key, like an ID of record in a data store
+func (diff *DiffOf[T]) SetKey(fn func(v T) string) {
+	diff.key = fn
+}
+
+// Truncated reports whether SES was truncated by Limit or MaxScriptElements
+func (diff *DiffOf[T]) Truncated() bool {
+	diff.composeIfNeeded()
+	return diff.truncated
+}
+
+// Editdistance returns edit distance between a and b.
+// Compose is called if it has not been called yet
+func (diff *DiffOf[T]) Editdistance() int {
+	diff.composeIfNeeded()
 	return diff.ed
 }
 
-// Lcs returns LCS (Longest Common Subsequence) between a and b
-func (diff *Diff) Lcs() []rune {
+// WithinDistance reports whether edit distance between a and b is less than or equal to tolerance.
+// It returns false immediately when the difference of the lengths of a and b, which is a lower bound
+// of edit distance unless IgnoreWhitespace is enabled, exceeds tolerance. Otherwise it searches
+// only until edit distance exceeds tolerance without composing diff, unless Compose has been called already
+func (diff *DiffOf[T]) WithinDistance(tolerance int) bool {
+	if tolerance < 0 || diff.blank == nil && max(diff.m-diff.n, diff.n-diff.m) > tolerance {
+		return false
+	}
+	if diff.composed && diff.ed >= 0 && !diff.fast {
+		return diff.ed <= tolerance
+	}
+	sub := diff.subDiff(0, diff.m, 0, diff.n)
+	sub.anchors = diff.anchors
+	sub.ignore = diff.ignore
+	sub.blank, sub.same = diff.blank, diff.same
+	sub.onlyEd = true
+	sub.maxEd = tolerance
+	sub.findPath(context.Background())
+	return sub.ed >= 0
+}
+
+// Equal reports whether a and b are equal with the same comparison as diff, that is,
+// edit distance between them is 0. It compares elements only until a difference is found
+// without composing diff, unless Compose has been called already.
+// With anchors, IgnoreRunes or IgnoreWhitespace, it searches like WithinDistance(0) instead
+func (diff *DiffOf[T]) Equal() bool {
+	if diff.composed {
+		return diff.ed == 0
+	}
+	if diff.anchors != nil || diff.ignore != nil || diff.blank != nil {
+		return diff.WithinDistance(0)
+	}
+	if diff.m != diff.n {
+		return false
+	}
+	for i := 0; i < diff.m; i++ {
+		if !diff.eq(diff.a[i], diff.b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Lcs returns LCS (Longest Common Subsequence) between a and b.
+// Compose is called if it has not been called yet
+func (diff *DiffOf[T]) Lcs() []T {
+	diff.composeIfNeeded()
 	return diff.lcs
 }
 
+// LcsLength returns the length of LCS between a and b, which is (len(a)+len(b)-Editdistance())/2.
+// It is available even when OnlyEd is enabled, and it is -1 when edit distance exceeds MaxEd
+func (diff *DiffOf[T]) LcsLength() int {
+	ed := diff.Editdistance()
+	if ed < 0 {
+		return -1
+	}
+	return (diff.m + diff.n - ed) / 2
+}
+
+// IndexPair is a pair of positions in a and b
+type IndexPair struct {
+	A, B int
+}
+
+// LcsIndices returns positions in a and b of each element of LCS
+func (diff *DiffOf[T]) LcsIndices() []IndexPair {
+	pairs := make([]IndexPair, 0, len(diff.Lcs()))
+	for _, e := range diff.Ses() {
+		if e.T == SesCommon {
+			pairs = append(pairs, IndexPair{A: e.AIndex, B: e.BIndex})
+		}
+	}
+	return pairs
+}
+
+// Ses return SES (Shortest Edit Script) between a and b.
+// Compose is called if it has not been called yet
+func (diff *DiffOf[T]) Ses() []SesElemOf[T] {
+	diff.composeIfNeeded()
+	return diff.ses
+}
+
+// OnlyAdds returns elements of SES added to b in order
+func (diff *DiffOf[T]) OnlyAdds() []SesElemOf[T] {
+	return diff.filterSes(SesAdd)
+}
+
+// OnlyDeletes returns elements of SES deleted from a in order
+func (diff *DiffOf[T]) OnlyDeletes() []SesElemOf[T] {
+	return diff.filterSes(SesDelete)
+}
+
+func (diff *DiffOf[T]) filterSes(t SesType) []SesElemOf[T] {
+	elems := make([]SesElemOf[T], 0)
+	for _, e := range diff.Ses() {
+		if e.T == t {
+			elems = append(elems, e)
+		}
+	}
+	return elems
+}
+
+// Path returns the endpoints of snakes on the path in edit graph in order.
+// X and Y of each point are positions in a and b, or in b and a when a is longer than b
+// and NoSwap is not enabled.
+// It returns nil when OnlyEd is enabled
+func (diff *DiffOf[T]) Path() []Point {
+	diff.composeIfNeeded()
+	if diff.points == nil {
+		return nil
+	}
+	path := make([]Point, len(diff.points))
+	copy(path, diff.points)
+	return path
+}
+
+// Ratio returns similarity between a and b in [0, 1].
+// It is 2*len(LCS) / (len(a)+len(b)), and 1 when both a and b are empty
+func (diff *DiffOf[T]) Ratio() float64 {
+	ed := diff.Editdistance()
+	total := diff.m + diff.n
+	if total == 0 {
+		return 1.0
+	}
+	return float64(total-ed) / float64(total)
+}
+
+// NormalizedDistance returns edit distance between a and b divided by len(a)+len(b) in [0, 1].
+// The denominator is len(a)+len(b) since edit distance counts deletions and additions,
+// so it reaches len(a)+len(b) when a and b have nothing in common, and it equals 1 - Ratio().
+// It is 0 when both a and b are empty, and 1 when edit distance exceeds MaxEd
+func (diff *DiffOf[T]) NormalizedDistance() float64 {
+	ed := diff.Editdistance()
+	total := diff.m + diff.n
+	if ed < 0 {
+		return 1.0
+	}
+	if total == 0 {
+		return 0.0
+	}
+	return math.Min(1.0, float64(ed)/float64(total))
+}
+
+// Stats returns the numbers of added, deleted and common elements in SES.
+// They are derived from edit distance, so they are available even when OnlyEd is enabled
+func (diff *DiffOf[T]) Stats() (added, deleted, common int) {
+	ed := diff.Editdistance()
+	if ed < 0 {
+		return 0, 0, 0
+	}
+	m, n := diff.lengths()
+	common = (m + n - ed) / 2
+	return n - common, m - common, common
+}
+
+// lengths returns the lengths of a and b in the original order
+func (diff *DiffOf[T]) lengths() (int, int) {
+	if diff.reverse {
+		return diff.n, diff.m
+	}
+	return diff.m, diff.n
+}
+
+// sequences returns a and b in the original order
+func (diff *DiffOf[T]) sequences() ([]T, []T) {
+	if diff.reverse {
+		return diff.b, diff.a
+	}
+	return diff.a, diff.b
+}
+
+func (diff *DiffOf[T]) composeIfNeeded() {
+	if !diff.composed {
+		diff.Compose()
+	}
+}
+
+// PrintSes prints shortest edit script between a and b
+func (diff *DiffOf[T]) PrintSes() {
+	diff.FprintSes(os.Stdout)
+}
+
+// SprintSes returns string about shortest edit script between a and b
+func (diff *DiffOf[T]) SprintSes() string {
+	var buf bytes.Buffer
+	diff.FprintSes(&buf)
+	return buf.String()
+}
+
+// FprintSes emit about shortest edit script between a and b to w
+func (diff *DiffOf[T]) FprintSes(w io.Writer) error {
+	for _, e := range diff.Ses() {
+		var err error
+		switch e.T {
+		case SesDelete:
+			_, err = fmt.Fprintf(w, "- %v\n", e.V)
+		case SesAdd:
+			_, err = fmt.Fprintf(w, "+ %v\n", e.V)
+		case SesCommon:
+			_, err = fmt.Fprintf(w, "  %v\n", e.V)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Lcs returns LCS (Longest Common Subsequence) string between a and b
 func (diff *Diff) LcsString() string {
-	return string(diff.lcs)
+	return string(diff.Lcs())
 }
 
-// Ses return SES (Shortest Edit Script) between a and b
-func (diff *Diff) Ses() []SesElem {
-	return diff.ses
+// BytesAdded returns the total length in UTF-8 of characters added in SES.
+// It is 0 when only edit distance is calculated
+func (diff *Diff) BytesAdded() int {
+	return diff.sesBytes(SesAdd)
+}
+
+// BytesDeleted returns the total length in UTF-8 of characters deleted in SES.
+// It is 0 when only edit distance is calculated
+func (diff *Diff) BytesDeleted() int {
+	return diff.sesBytes(SesDelete)
+}
+
+func (diff *Diff) sesBytes(t SesType) int {
+	n := 0
+	for _, e := range diff.Ses() {
+		if e.T == t {
+			n += PositionUTF8.width(e.V)
+		}
+	}
+	return n
 }
 
 // PrintSes prints shortest edit script between a and b
 func (diff *Diff) PrintSes() {
-	fmt.Print(diff.SprintSes())
+	diff.FprintSes(os.Stdout)
 }
 
 // SprintSes returns string about shortest edit script between a and b
@@ -113,80 +721,427 @@ func (diff *Diff) SprintSes() string {
 }
 
 // FprintSes emit about shortest edit script between a and b to w
-func (diff *Diff) FprintSes(w io.Writer) {
-	for _, e := range diff.ses {
-		switch e.t {
+func (diff *Diff) FprintSes(w io.Writer) error {
+	for _, e := range diff.Ses() {
+		var err error
+		switch e.T {
 		case SesDelete:
-			fmt.Fprintf(w, "- %c\n", e.e)
+			_, err = fmt.Fprintf(w, "- %s\n", diff.formatRune(e.V))
 		case SesAdd:
-			fmt.Fprintf(w, "+ %c\n", e.e)
+			_, err = fmt.Fprintf(w, "+ %s\n", diff.formatRune(e.V))
 		case SesCommon:
-			fmt.Fprintf(w, "  %c\n", e.e)
+			_, err = fmt.Fprintf(w, "  %s\n", diff.formatRune(e.V))
+		}
+		if err != nil {
+			return err
 		}
 	}
+	return nil
 }
 
 // Compose composes diff between a and b
-func (diff *Diff) Compose() {
-	fp := make([]int, diff.m+diff.n+3)
-	diff.path = make([]int, diff.m+diff.n+3)
-	diff.pointWithRoute = make([]PointWithRoute, 0)
+func (diff *DiffOf[T]) Compose() {
+	diff.ComposeContext(context.Background())
+}
 
-	for i := range fp {
-		fp[i] = -1
-		diff.path[i] = -1
+// ComposeContext composes diff between a and b like Compose.
+// It returns ctx.Err() when ctx is done before composing is finished
+func (diff *DiffOf[T]) ComposeContext(ctx context.Context) error {
+	points := diff.resetResult()
+	epc, err := diff.findPath(ctx)
+	if err != nil {
+		return err
 	}
+	diff.recordResult(points, epc)
+	return nil
+}
 
-	offset := diff.m + 1
-	delta := diff.n - diff.m
-	for p := 0; ; p++ {
+// resetResult clears the result of the previous Compose, and returns the buffer of the path to reuse
+func (diff *DiffOf[T]) resetResult() []Point {
+	if diff.composed {
+		// results of the previous Compose may still be used by the caller
+		diff.lcs, diff.ses = nil, nil
+	}
+	diff.lcs = diff.lcs[:0]
+	diff.ses = diff.ses[:0]
+	diff.composed = false
+	diff.stepping = false
+	diff.truncated = false
+	points := diff.points[:0]
+	diff.points = nil
+	diff.frontiers = nil
+	diff.work = new(WorkStats)
+	return points
+}
 
-		for k := -p; k <= delta-1; k++ {
-			fp[k+offset] = diff.snake(k, fp[k-1+offset]+1, fp[k+1+offset], offset)
+// recordResult records the path epc found in reverse order with SES and LCS along it
+func (diff *DiffOf[T]) recordResult(points, epc []Point) {
+	diff.composed = true
+	if diff.onlyEd || diff.ed < 0 {
+		return
+	}
+	diff.points = resize(points, len(epc))
+	for i, p := range epc {
+		diff.points[len(epc)-1-i] = p
+	}
+	edits := 0
+	diff.recordSeq(epc, func(e SesElemOf[T]) bool {
+		if diff.maxElems >= 0 && len(diff.ses) >= diff.maxElems {
+			diff.truncated = true
+			diff.cutSes(changeBoundary(diff.ses, e))
+			return false
+		}
+		if e.T == SesCommon {
+			diff.lcs = append(diff.lcs, e.V)
+		} else {
+			if diff.limit >= 0 && edits >= diff.limit {
+				diff.truncated = true
+				return false
+			}
+			edits++
 		}
+		diff.ses = append(diff.ses, e)
+		return true
+	})
+}
 
-		for k := delta + p; k >= delta+1; k-- {
-			fp[k+offset] = diff.snake(k, fp[k-1+offset]+1, fp[k+1+offset], offset)
+// changeBoundary returns the end of ses followed by next without splitting
+// a run of changes between common elements. It returns len(ses) when ses has no common elements
+func changeBoundary[T any](ses []SesElemOf[T], next SesElemOf[T]) int {
+	if next.T == SesCommon {
+		return len(ses)
+	}
+	for i := len(ses); i > 0; i-- {
+		if ses[i-1].T == SesCommon {
+			return i
 		}
+	}
+	return len(ses)
+}
+
+// cutSes cuts SES to the first n elements with LCS
+func (diff *DiffOf[T]) cutSes(n int) {
+	for _, e := range diff.ses[n:] {
+		if e.T == SesCommon {
+			diff.lcs = diff.lcs[:len(diff.lcs)-1]
+		}
+	}
+	diff.ses = diff.ses[:n]
+}
+
+// EachSes calls fn for each element of SES between a and b in order until fn returns false.
+// If Compose has not been called yet, elements are passed to fn as soon as they are found
+// without materializing the whole SES
+func (diff *DiffOf[T]) EachSes(fn func(SesElemOf[T]) bool) {
+	if diff.composed && !diff.onlyEd {
+		for _, e := range diff.ses {
+			if !fn(e) {
+				return
+			}
+		}
+		return
+	}
+	onlyEd := diff.onlyEd
+	diff.onlyEd = false
+	epc, _ := diff.findPath(context.Background())
+	diff.onlyEd = onlyEd
+	diff.recordSeq(epc, fn)
+}
 
-		fp[delta+offset] = diff.snake(delta, fp[delta-1+offset]+1, fp[delta+1+offset], offset)
+// SesChan returns the channel emitting each element of SES between a and b in order like EachSes.
+// The channel is closed after the last element, or when ctx is done, which stops
+// the goroutine emitting elements when the consumer stops receiving early.
+// diff must not be used until the channel is closed
+func (diff *DiffOf[T]) SesChan(ctx context.Context) <-chan SesElemOf[T] {
+	ch := make(chan SesElemOf[T])
+	go func() {
+		defer close(ch)
+		diff.EachSes(func(e SesElemOf[T]) bool {
+			select {
+			case ch <- e:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		})
+	}()
+	return ch
+}
 
-		if fp[delta+offset] >= diff.n {
-			diff.ed = delta + 2*p
+func (diff *DiffOf[T]) findPath(ctx context.Context) ([]Point, error) {
+	if diff.anchors != nil {
+		return diff.findAnchoredPath(ctx)
+	}
+	if diff.ignore != nil {
+		return diff.findIgnoringPath(ctx)
+	}
+	if diff.blank != nil {
+		return diff.findCollapsedPath(ctx)
+	}
+	if diff.alignSuffix {
+		return diff.findSuffixAlignedPath(ctx)
+	}
+	if diff.recordFp {
+		return diff.findRecordedPath(ctx)
+	}
+	if (diff.m == 0 || diff.n == 0) && diff.tail == 0 {
+		return diff.straightPath(), nil
+	}
+	prefix, suffix := diff.commonAffixes()
+	if prefix > 0 || suffix > 0 {
+		return diff.findTrimmedPath(ctx, prefix, suffix)
+	}
+	if diff.fast {
+		return diff.findFastPath(ctx)
+	}
+	if diff.linearSpace && !diff.onlyEd {
+		return diff.searchLinearPath(ctx)
+	}
+	return diff.searchPath(ctx)
+}
+
+// findRecordedPath records frontiers by searching the whole edit graph in the same way as the paper,
+// and then finds the path as usual so that the result is not changed by RecordFrontiers
+func (diff *DiffOf[T]) findRecordedPath(ctx context.Context) ([]Point, error) {
+	rec := diff.subDiff(0, diff.m, 0, diff.n)
+	rec.progress, rec.work = nil, nil
+	rec.onlyEd = true
+	rec.recordFp = true
+	if _, err := rec.searchPath(ctx); err != nil {
+		return nil, err
+	}
+	diff.frontiers = rec.frontiers
+	diff.recordFp = false
+	defer func() { diff.recordFp = true }()
+	return diff.findPath(ctx)
+}
+
+// straightPath returns the path when a or b is empty, which consists of additions or deletions only
+func (diff *DiffOf[T]) straightPath() []Point {
+	diff.ed = diff.m + diff.n
+	if diff.maxEd >= 0 && diff.ed > diff.maxEd {
+		diff.ed = -1
+		return nil
+	}
+	if diff.onlyEd {
+		return nil
+	}
+	epc := make([]Point, 0, diff.ed+1)
+	for i := diff.ed; i >= 0; i-- {
+		if diff.m == 0 {
+			epc = append(epc, Point{X: 0, Y: i})
+		} else {
+			epc = append(epc, Point{X: i, Y: 0})
+		}
+	}
+	return epc
+}
+
+// commonAffixes returns the lengths of common prefix and suffix of a and b
+func (diff *DiffOf[T]) commonAffixes() (int, int) {
+	prefix := 0
+	if diff.match != nil {
+		prefix = diff.match(diff.a, diff.b)
+	} else {
+		for prefix < diff.m && prefix < diff.n && diff.equalAt(prefix, prefix) {
+			prefix++
+		}
+	}
+	suffix := 0
+	for prefix+suffix < diff.m && prefix+suffix < diff.n && diff.equalAt(diff.m-1-suffix, diff.n-1-suffix) {
+		suffix++
+	}
+	return prefix, suffix
+}
+
+// findTrimmedPath searches the path only between common prefix and suffix, letting snakes run into the suffix,
+// and returns it with points shifted to absolute positions in a and b
+func (diff *DiffOf[T]) findTrimmedPath(ctx context.Context, prefix, suffix int) ([]Point, error) {
+	sub := diff.subDiff(prefix, diff.m-suffix, prefix, diff.n-suffix)
+	sub.onlyEd = diff.onlyEd
+	sub.maxEd = diff.maxEd
+	sub.linearSpace = diff.linearSpace
+	sub.fast = diff.fast
+	if !diff.onlyEd {
+		diff.runIntoSuffix(sub, prefix, prefix, suffix)
+	}
+	epc, err := sub.findPath(ctx)
+	diff.ed = sub.ed
+	if err != nil || epc == nil {
+		return nil, err
+	}
+	return diff.shiftPath(epc, prefix, suffix), nil
+}
+
+// runIntoSuffix lets snakes of sub searching from (x, y) to the end run into tail elements,
+// common suffix trimmed after a and b, so that the path is aligned in the same way as without trimming
+func (diff *DiffOf[T]) runIntoSuffix(sub *DiffOf[T], x, y, tail int) {
+	sub.a, sub.b = diff.a[x:], diff.b[y:]
+	if diff.hashA != nil {
+		sub.hashA, sub.hashB = diff.hashA[x:], diff.hashB[y:]
+	}
+	sub.tail = tail
+}
+
+// shiftPath shifts the points of the path between common prefix and suffix in reverse order
+// to absolute positions in a and b, and adds the end of the path when common suffix is trimmed.
+// The path may end in the suffix when it reaches the diagonal of the end there
+func (diff *DiffOf[T]) shiftPath(epc []Point, prefix, suffix int) []Point {
+	shifted := make([]Point, 0, len(epc)+1)
+	if suffix > 0 && (len(epc) == 0 || epc[0] != Point{X: diff.m - prefix, Y: diff.n - prefix}) {
+		shifted = append(shifted, Point{X: diff.m, Y: diff.n})
+	}
+	for _, p := range epc {
+		shifted = append(shifted, Point{X: p.X + prefix, Y: p.Y + prefix})
+	}
+	return shifted
+}
+
+// searchPath calculates edit distance and returns the farthest points of the path
+// on the edit graph in reverse order. It returns nil when only edit distance is needed.
+// ctx is checked on every iteration of p
+func (diff *DiffOf[T]) searchPath(ctx context.Context) ([]Point, error) {
+	diff.beginSearch()
+	for p := 0; ; p++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		diff.beginIteration(p)
+		if diff.maxP > 0 && p > diff.maxP {
+			return diff.farthestRoute(diff.fp, diff.m+1), nil
+		}
+		if diff.exceedsMaxEd(p) {
+			diff.ed = -1
+			return nil, nil
+		}
+		if diff.searchIteration(p) {
 			break
 		}
 	}
+	return diff.endSearch(), nil
+}
+
+// beginSearch initializes the farthest points and routes before iterating p
+func (diff *DiffOf[T]) beginSearch() {
+	diff.fp = resize(diff.fp, diff.m+diff.n+3)
+	for i := range diff.fp {
+		diff.fp[i] = -1
+	}
+	if !diff.onlyEd {
+		diff.path = resize(diff.path, diff.m+diff.n+3)
+		diff.pointWithRoute = diff.pointWithRoute[:0]
+		for i := range diff.path {
+			diff.path[i] = -1
+		}
+	}
+	for i := range diff.cross {
+		diff.cross[i] = Point{X: -1, Y: -1}
+	}
+	diff.partial = false
+}
+
+// beginIteration reports progress of the iteration of p
+func (diff *DiffOf[T]) beginIteration(p int) {
+	if diff.progress != nil {
+		diff.progress(p)
+	}
+	if diff.work != nil {
+		diff.work.Iterations++
+	}
+}
+
+// exceedsMaxEd reports whether edit distance exceeds MaxEd when the path is not found until p
+func (diff *DiffOf[T]) exceedsMaxEd(p int) bool {
+	delta := diff.n - diff.m
+	return diff.maxEd >= 0 && max(delta, -delta)+2*p > diff.maxEd
+}
+
+// searchIteration extends the farthest points on diagonals for p,
+// and reports whether the path reaches the end with edit distance
+func (diff *DiffOf[T]) searchIteration(p int) bool {
+	fp := diff.fp
+	offset := diff.m + 1
+	delta := diff.n - diff.m
+	for k := min(-p, delta-p); k <= delta-1; k++ {
+		fp[k+offset] = diff.snake(k, fp[k-1+offset]+1, fp[k+1+offset], offset)
+	}
+
+	for k := max(delta+p, p); k >= delta+1; k-- {
+		fp[k+offset] = diff.snake(k, fp[k-1+offset]+1, fp[k+1+offset], offset)
+	}
+
+	fp[delta+offset] = diff.snake(delta, fp[delta-1+offset]+1, fp[delta+1+offset], offset)
+	if diff.recordFp {
+		diff.frontiers = append(diff.frontiers, append([]int(nil), fp...))
+	}
+
+	if fp[delta+offset] >= diff.n {
+		diff.ed = max(delta, -delta) + 2*p
+		return true
+	}
+	return false
+}
+
+// endSearch returns the points of the path found in reverse order, or nil when only edit distance is needed
+func (diff *DiffOf[T]) endSearch() []Point {
+	offset := diff.m + 1
+	delta := diff.n - diff.m
+	if diff.cross != nil {
+		diff.mid = diff.cross[delta+offset]
+	}
 
 	if diff.onlyEd {
-		return
+		return nil
 	}
 
-	r := diff.path[delta+offset]
+	return diff.route(diff.path[delta+offset])
+}
+
+// route returns the points on the route ending at pointWithRoute[r] in reverse order
+func (diff *DiffOf[T]) route(r int) []Point {
 	epc := make([]Point, 0)
 	for r != -1 {
-		epc = append(epc, Point{x: diff.pointWithRoute[r].x, y: diff.pointWithRoute[r].y})
+		epc = append(epc, Point{X: diff.pointWithRoute[r].x, Y: diff.pointWithRoute[r].y})
 		r = diff.pointWithRoute[r].r
 	}
-	diff.recordSeq(epc)
+	return epc
 }
 
-func (diff *Diff) snake(k, p, pp, offset int) int {
-	r := 0
+func (diff *DiffOf[T]) snake(k, p, pp, offset int) int {
+	from := k + 1
 	if p > pp {
-		r = diff.path[k-1+offset]
-	} else {
-		r = diff.path[k+1+offset]
+		from = k - 1
 	}
 
 	y := max(p, pp)
 	x := y - k
+	y0, x0 := y, x
+
+	m, n := diff.m, diff.n
+	if k != n-m {
+		// snakes run into the trimmed common suffix as without trimming, except on the diagonal of the end
+		m, n = m+diff.tail, n+diff.tail
+	}
+	if diff.match != nil && x < m && y < n {
+		d := diff.match(diff.a[x:m], diff.b[y:n])
+		x, y = x+d, y+d
+	} else {
+		for x < m && y < n && diff.equalAt(x, y) {
+			x++
+			y++
+		}
+	}
 
-	for x < diff.m && y < diff.n && diff.a[x] == diff.b[y] {
-		x++
-		y++
+	if diff.work != nil {
+		diff.work.SnakeSteps += x - x0
+	}
+
+	if diff.cross != nil {
+		diff.cross[k+offset] = diff.crossing(diff.cross[from+offset], x0, y0, x, y)
 	}
 
 	if !diff.onlyEd {
+		r := diff.path[from+offset]
 		diff.path[k+offset] = len(diff.pointWithRoute)
 		diff.pointWithRoute = append(diff.pointWithRoute, PointWithRoute{x: x, y: y, r: r})
 	}
@@ -194,30 +1149,50 @@ func (diff *Diff) snake(k, p, pp, offset int) int {
 	return y
 }
 
-func (diff *Diff) recordSeq(epc []Point) {
+func (diff *DiffOf[T]) recordSeq(epc []Point, emit func(SesElemOf[T]) bool) {
+	if diff.key != nil {
+		emitElem := emit
+		emit = func(e SesElemOf[T]) bool {
+			e.Key = diff.key(e.V)
+			return emitElem(e)
+		}
+	}
+	if diff.deletesFirst {
+		emit = deletesFirst(emit)
+		defer emit(SesElemOf[T]{T: SesCommon, AIndex: -1, BIndex: -1})
+	}
 	x, y := 1, 1
 	px, py := 0, 0
 	for i := len(epc) - 1; i >= 0; i-- {
-		for (px < epc[i].x) || (py < epc[i].y) {
-			if (epc[i].y - epc[i].x) > (py - px) {
-				t := SesAdd
+		for (px < epc[i].X) || (py < epc[i].Y) {
+			if (epc[i].Y - epc[i].X) > (py - px) {
+				e := SesElemOf[T]{V: diff.b[py], T: SesAdd, AIndex: -1, BIndex: py}
 				if diff.reverse {
-					t = SesDelete
+					e.T, e.AIndex, e.BIndex = SesDelete, py, -1
+				}
+				if !emit(e) {
+					return
 				}
-				diff.ses = append(diff.ses, SesElem{e: diff.b[py], t: t})
 				y++
 				py++
-			} else if epc[i].y-epc[i].x < py-px {
-				t := SesDelete
+			} else if epc[i].Y-epc[i].X < py-px {
+				e := SesElemOf[T]{V: diff.a[px], T: SesDelete, AIndex: px, BIndex: -1}
 				if diff.reverse {
-					t = SesAdd
+					e.T, e.AIndex, e.BIndex = SesAdd, -1, px
+				}
+				if !emit(e) {
+					return
 				}
-				diff.ses = append(diff.ses, SesElem{e: diff.a[px], t: t})
 				x++
 				px++
 			} else {
-				diff.lcs = append(diff.lcs, diff.a[px])
-				diff.ses = append(diff.ses, SesElem{e: diff.a[px], t: SesCommon})
+				e := SesElemOf[T]{V: diff.a[px], T: SesCommon, AIndex: px, BIndex: py}
+				if diff.reverse {
+					e.V, e.AIndex, e.BIndex = diff.b[py], py, px
+				}
+				if !emit(e) {
+					return
+				}
 				x++
 				y++
 				px++
@@ -226,3 +1201,37 @@ func (diff *Diff) recordSeq(epc []Point) {
 		}
 	}
 }
+
+// deletesFirst returns emit which buffers additions until a common element comes,
+// so that deletions are passed to emit before additions.
+// A common element with negative indices flushes the buffer without being passed
+func deletesFirst[T any](emit func(SesElemOf[T]) bool) func(SesElemOf[T]) bool {
+	adds := make([]SesElemOf[T], 0)
+	stopped := false
+	return func(e SesElemOf[T]) bool {
+		if stopped {
+			return false
+		}
+		if e.T == SesAdd {
+			adds = append(adds, e)
+			return true
+		}
+		if e.T == SesCommon {
+			for _, add := range adds {
+				if !emit(add) {
+					stopped = true
+					return false
+				}
+			}
+			adds = adds[:0]
+			if e.AIndex < 0 {
+				return true
+			}
+		}
+		if !emit(e) {
+			stopped = true
+			return false
+		}
+		return true
+	}
+}
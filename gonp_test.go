@@ -1,7 +1,15 @@
 package gonp
 
 import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"strings"
 	"testing"
+	"unicode"
+	"unicode/utf8"
 )
 
 func equalsSesElemArray(ses1, ses2 []SesElem) bool {
@@ -10,7 +18,19 @@ func equalsSesElemArray(ses1, ses2 []SesElem) bool {
 		return true
 	}
 	for i := 0; i < m; i++ {
-		if ses1[i].e != ses2[i].e || ses1[i].t != ses2[i].t {
+		if ses1[i].V != ses2[i].V || ses1[i].T != ses2[i].T {
+			return false
+		}
+	}
+	return true
+}
+
+func equalsSesElemOfArray[T comparable](ses1, ses2 []SesElemOf[T]) bool {
+	if len(ses1) != len(ses2) {
+		return false
+	}
+	for i := range ses1 {
+		if ses1[i].V != ses2[i].V || ses1[i].T != ses2[i].T {
 			return false
 		}
 	}
@@ -29,10 +49,10 @@ func TestDiff1(t *testing.T) {
 	lcs := diff.LcsString()
 	sesActual := diff.Ses()
 	sesExpected := []SesElem{
-		{e: 'a', t: SesCommon},
-		{e: 'b', t: SesCommon},
-		{e: 'c', t: SesDelete},
-		{e: 'd', t: SesAdd},
+		{V: 'a', T: SesCommon},
+		{V: 'b', T: SesCommon},
+		{V: 'c', T: SesDelete},
+		{V: 'd', T: SesAdd},
 	}
 	assert(t, diff.Editdistance() == 2)
 	assert(t, lcs == "ab")
@@ -45,15 +65,15 @@ func TestDiff2(t *testing.T) {
 	lcs := diff.LcsString()
 	sesActual := diff.Ses()
 	sesExpected := []SesElem{
-		{e: 'd', t: SesAdd},
-		{e: 'a', t: SesCommon},
-		{e: 'b', t: SesDelete},
-		{e: 'c', t: SesCommon},
-		{e: 'd', t: SesDelete},
-		{e: 'e', t: SesDelete},
-		{e: 'f', t: SesCommon},
-		{e: 'e', t: SesAdd},
-		{e: 'a', t: SesAdd},
+		{V: 'd', T: SesAdd},
+		{V: 'a', T: SesCommon},
+		{V: 'b', T: SesDelete},
+		{V: 'c', T: SesCommon},
+		{V: 'd', T: SesDelete},
+		{V: 'e', T: SesDelete},
+		{V: 'f', T: SesCommon},
+		{V: 'e', T: SesAdd},
+		{V: 'a', T: SesAdd},
 	}
 	assert(t, diff.Editdistance() == 6)
 	assert(t, lcs == "acf")
@@ -66,20 +86,20 @@ func TestDiff3(t *testing.T) {
 	lcs := diff.LcsString()
 	sesActual := diff.Ses()
 	sesExpected := []SesElem{
-		{e: 'a', t: SesCommon},
-		{e: 'c', t: SesCommon},
-		{e: 'e', t: SesAdd},
-		{e: 'b', t: SesCommon},
-		{e: 'd', t: SesCommon},
-		{e: 'e', t: SesDelete},
-		{e: 'a', t: SesCommon},
-		{e: 'c', t: SesDelete},
-		{e: 'b', t: SesCommon},
-		{e: 'b', t: SesAdd},
-		{e: 'a', t: SesAdd},
-		{e: 'b', t: SesAdd},
-		{e: 'e', t: SesCommon},
-		{e: 'd', t: SesCommon},
+		{V: 'a', T: SesCommon},
+		{V: 'c', T: SesCommon},
+		{V: 'e', T: SesAdd},
+		{V: 'b', T: SesCommon},
+		{V: 'd', T: SesCommon},
+		{V: 'e', T: SesDelete},
+		{V: 'a', T: SesCommon},
+		{V: 'c', T: SesDelete},
+		{V: 'b', T: SesCommon},
+		{V: 'b', T: SesAdd},
+		{V: 'a', T: SesAdd},
+		{V: 'b', T: SesAdd},
+		{V: 'e', T: SesCommon},
+		{V: 'd', T: SesCommon},
 	}
 	assert(t, diff.Editdistance() == 6)
 	assert(t, lcs == "acbdabed")
@@ -92,14 +112,14 @@ func TestDiff4(t *testing.T) {
 	lcs := diff.LcsString()
 	sesActual := diff.Ses()
 	sesExpected := []SesElem{
-		{e: 'a', t: SesDelete},
-		{e: 'b', t: SesCommon},
-		{e: 'd', t: SesAdd},
-		{e: 'c', t: SesCommon},
-		{e: 'a', t: SesAdd},
-		{e: 'b', t: SesCommon},
-		{e: 'd', t: SesDelete},
-		{e: 'a', t: SesCommon},
+		{V: 'a', T: SesDelete},
+		{V: 'b', T: SesCommon},
+		{V: 'd', T: SesAdd},
+		{V: 'c', T: SesCommon},
+		{V: 'a', T: SesAdd},
+		{V: 'b', T: SesCommon},
+		{V: 'd', T: SesDelete},
+		{V: 'a', T: SesCommon},
 	}
 	assert(t, diff.Editdistance() == 4)
 	assert(t, lcs == "bcba")
@@ -112,12 +132,12 @@ func TestDiff5(t *testing.T) {
 	lcs := diff.LcsString()
 	sesActual := diff.Ses()
 	sesExpected := []SesElem{
-		{e: 'b', t: SesCommon},
-		{e: 'o', t: SesCommon},
-		{e: 'k', t: SesCommon},
-		{e: 'k', t: SesCommon},
-		{e: 'k', t: SesAdd},
-		{e: 'o', t: SesCommon},
+		{V: 'b', T: SesCommon},
+		{V: 'o', T: SesCommon},
+		{V: 'k', T: SesCommon},
+		{V: 'k', T: SesCommon},
+		{V: 'k', T: SesAdd},
+		{V: 'o', T: SesCommon},
 	}
 	assert(t, diff.Editdistance() == 1)
 	assert(t, lcs == "bokko")
@@ -141,7 +161,7 @@ func TestDiffEmptyString2(t *testing.T) {
 	lcs := diff.LcsString()
 	sesActual := diff.Ses()
 	sesExpected := []SesElem{
-		{e: 'a', t: SesDelete},
+		{V: 'a', T: SesDelete},
 	}
 	assert(t, diff.Editdistance() == 1)
 	assert(t, lcs == "")
@@ -154,7 +174,7 @@ func TestDiffEmptyString3(t *testing.T) {
 	lcs := diff.LcsString()
 	sesActual := diff.Ses()
 	sesExpected := []SesElem{
-		{e: 'b', t: SesAdd},
+		{V: 'b', T: SesAdd},
 	}
 	assert(t, diff.Editdistance() == 1)
 	assert(t, lcs == "")
@@ -167,11 +187,11 @@ func TestDiffMultiByteString(t *testing.T) {
 	lcs := diff.LcsString()
 	sesActual := diff.Ses()
 	sesExpected := []SesElem{
-		{e: '久', t: SesCommon},
-		{e: '保', t: SesCommon},
-		{e: '竜', t: SesDelete},
-		{e: '達', t: SesAdd},
-		{e: '彦', t: SesCommon},
+		{V: '久', T: SesCommon},
+		{V: '保', T: SesCommon},
+		{V: '竜', T: SesDelete},
+		{V: '達', T: SesAdd},
+		{V: '彦', T: SesCommon},
 	}
 	assert(t, diff.Editdistance() == 2)
 	assert(t, lcs == "久保彦")
@@ -196,3 +216,1012 @@ func TestDiffSprintSes(t *testing.T) {
 	ses := diff.SprintSes()
 	assert(t, ses == "  a\n  \n\n- b\n+ 1\n  \n\n  c\n")
 }
+
+type record struct {
+	id   int
+	body string
+}
+
+func TestDiffSlice(t *testing.T) {
+	a := []record{{1, "foo"}, {2, "bar"}, {3, "baz"}}
+	b := []record{{1, "foo"}, {3, "baz"}, {4, "qux"}}
+	diff := NewSlice(a, b, func(x, y record) bool {
+		return x.id == y.id
+	})
+	diff.Compose()
+	sesActual := diff.Ses()
+	sesExpected := []SesElemOf[record]{
+		{V: record{1, "foo"}, T: SesCommon},
+		{V: record{2, "bar"}, T: SesDelete},
+		{V: record{3, "baz"}, T: SesCommon},
+		{V: record{4, "qux"}, T: SesAdd},
+	}
+	assert(t, diff.Editdistance() == 2)
+	assert(t, len(diff.Lcs()) == 2)
+	assert(t, equalsSesElemOfArray(sesActual, sesExpected))
+}
+
+func TestDiffInterface(t *testing.T) {
+	a := []interface{}{1, "foo", 2.5, nil}
+	b := []interface{}{1, 2.5, "bar", nil}
+	diff := NewInterface(a, b, func(x, y interface{}) bool {
+		return x == y
+	})
+	diff.Compose()
+	sesExpected := []SesElemOf[interface{}]{
+		{V: 1, T: SesCommon},
+		{V: "foo", T: SesDelete},
+		{V: 2.5, T: SesCommon},
+		{V: "bar", T: SesAdd},
+		{V: nil, T: SesCommon},
+	}
+	assert(t, diff.Editdistance() == 2)
+	ses := diff.Ses()
+	assert(t, len(ses) == len(sesExpected))
+	for i := range ses {
+		assert(t, ses[i].V == sesExpected[i].V && ses[i].T == sesExpected[i].T)
+	}
+}
+
+func TestDiffSesIndex(t *testing.T) {
+	for _, c := range []struct{ a, b string }{{"abc", "abd"}, {"abcd", "xbc"}, {"", "ab"}, {"ab", ""}} {
+		diff := New(c.a, c.b)
+		diff.Compose()
+		a, b := []rune(c.a), []rune(c.b)
+		ai, bi := 0, 0
+		for _, e := range diff.Ses() {
+			switch e.T {
+			case SesDelete:
+				assert(t, e.AIndex == ai && e.BIndex == -1 && a[e.AIndex] == e.V)
+				ai++
+			case SesAdd:
+				assert(t, e.AIndex == -1 && e.BIndex == bi && b[e.BIndex] == e.V)
+				bi++
+			case SesCommon:
+				assert(t, e.AIndex == ai && e.BIndex == bi && a[e.AIndex] == e.V && b[e.BIndex] == e.V)
+				ai++
+				bi++
+			}
+		}
+		assert(t, ai == len(a) && bi == len(b))
+	}
+}
+
+func TestDiffWithoutCompose(t *testing.T) {
+	diff := New("abc", "abd")
+	assert(t, diff.LcsString() == "ab")
+	assert(t, diff.Editdistance() == 2)
+	assert(t, len(diff.Ses()) == 4)
+	assert(t, diff.SprintSes() == "  a\n  b\n- c\n+ d\n")
+}
+
+func TestDiffComposeTwice(t *testing.T) {
+	diff := New("abc", "abd")
+	diff.Compose()
+	diff.Compose()
+	assert(t, diff.LcsString() == "ab")
+	assert(t, len(diff.Ses()) == 4)
+}
+
+func TestDiffRatio(t *testing.T) {
+	assert(t, New("abc", "abc").Ratio() == 1.0)
+	assert(t, New("abc", "xyz").Ratio() == 0.0)
+	assert(t, New("", "").Ratio() == 1.0)
+	assert(t, New("abcd", "abxy").Ratio() == 0.5)
+	diff := New("abcd", "abxy")
+	diff.OnlyEd()
+	assert(t, diff.Ratio() == 0.5)
+}
+
+func TestDiffEachSes(t *testing.T) {
+	diff := New("abc", "abd")
+	ses := make([]SesElem, 0)
+	diff.EachSes(func(e SesElem) bool {
+		ses = append(ses, e)
+		return true
+	})
+	assert(t, equalsSesElemOfArray(ses, []SesElem{
+		{V: 'a', T: SesCommon},
+		{V: 'b', T: SesCommon},
+		{V: 'c', T: SesDelete},
+		{V: 'd', T: SesAdd},
+	}))
+
+	n := 0
+	diff.EachSes(func(e SesElem) bool {
+		n++
+		return e.T == SesCommon
+	})
+	assert(t, n == 3)
+}
+
+func TestDiffSesChan(t *testing.T) {
+	diff := New("abc", "abd")
+	ses := make([]SesElem, 0)
+	for e := range diff.SesChan(context.Background()) {
+		ses = append(ses, e)
+	}
+	assert(t, equalsSesElemOfArray(ses, []SesElem{
+		{V: 'a', T: SesCommon},
+		{V: 'b', T: SesCommon},
+		{V: 'c', T: SesDelete},
+		{V: 'd', T: SesAdd},
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := New("acbdeacbed", "acebdabbabed").SesChan(ctx)
+	<-ch
+	cancel()
+	// the channel is closed without receiving every element
+	n := 0
+	for range ch {
+		n++
+	}
+	assert(t, n <= 1)
+}
+
+func TestDiffLimit(t *testing.T) {
+	diff := New("abcdef", "dacfea")
+	diff.Limit(2)
+	diff.Compose()
+	sesExpected := []SesElem{
+		{V: 'd', T: SesAdd},
+		{V: 'a', T: SesCommon},
+		{V: 'b', T: SesDelete},
+		{V: 'c', T: SesCommon},
+	}
+	assert(t, diff.Editdistance() == 6)
+	assert(t, diff.Truncated())
+	assert(t, equalsSesElemOfArray(diff.Ses(), sesExpected))
+
+	diff = New("abc", "abd")
+	diff.Limit(2)
+	diff.Compose()
+	assert(t, !diff.Truncated())
+	assert(t, len(diff.Ses()) == 4)
+}
+
+func TestDiffMaxScriptElements(t *testing.T) {
+	diff := New("abcdefgh", "abxydefz")
+	diff.MaxScriptElements(4)
+	diff.Compose()
+	// the run of changes after "ab" is not split
+	assert(t, diff.Editdistance() == 6)
+	assert(t, diff.Truncated())
+	assert(t, diff.LcsString() == "ab")
+	assert(t, equalsSesElemOfArray(diff.Ses(), []SesElem{
+		{V: 'a', T: SesCommon},
+		{V: 'b', T: SesCommon},
+	}))
+
+	diff = NewWith("abcdefgh", "abxydefz", MaxScriptElements(7))
+	diff.Compose()
+	assert(t, diff.Truncated())
+	assert(t, diff.LcsString() == "abde")
+	assert(t, len(diff.Ses()) == 7)
+
+	// a run of changes is split when there is no common element before it
+	diff = NewWith("abc", "xyz", MaxScriptElements(2))
+	assert(t, diff.Truncated())
+	assert(t, len(diff.Ses()) == 2)
+
+	diff = NewWith("abcdefgh", "abxydefz", MaxScriptElements(11))
+	assert(t, !diff.Truncated())
+	assert(t, len(diff.Ses()) == 11)
+}
+
+// errWriter writes up to n bytes to buf and fails after that
+type errWriter struct {
+	buf bytes.Buffer
+	n   int
+}
+
+func (w *errWriter) Write(p []byte) (int, error) {
+	if w.buf.Len()+len(p) > w.n {
+		return 0, io.ErrShortWrite
+	}
+	return w.buf.Write(p)
+}
+
+func TestDiffFprintSesError(t *testing.T) {
+	diff := New("abc", "abd")
+	assert(t, diff.FprintSes(&errWriter{}) == io.ErrShortWrite)
+	var buf bytes.Buffer
+	assert(t, diff.FprintSes(&buf) == nil)
+	assert(t, buf.String() == "  a\n  b\n- c\n+ d\n")
+}
+
+func TestDiffIgnoreCase(t *testing.T) {
+	diff := New("aBcD", "AbX")
+	diff.IgnoreCase()
+	diff.Compose()
+	sesExpected := []SesElem{
+		{V: 'a', T: SesCommon},
+		{V: 'B', T: SesCommon},
+		{V: 'c', T: SesDelete},
+		{V: 'D', T: SesDelete},
+		{V: 'X', T: SesAdd},
+	}
+	assert(t, diff.Editdistance() == 3)
+	assert(t, diff.LcsString() == "aB")
+	assert(t, equalsSesElemOfArray(diff.Ses(), sesExpected))
+}
+
+func TestDiffIgnoreWhitespace(t *testing.T) {
+	diff := New("if a {\n\tb()\n}", "if a {\n    b()\n}")
+	diff.IgnoreWhitespace()
+	diff.Compose()
+	assert(t, diff.Editdistance() == 0)
+	assert(t, diff.Equal())
+	for _, e := range diff.Ses() {
+		assert(t, e.T == SesCommon || (e.T == SesDelete && e.V == '\t') || (e.T == SesAdd && e.V == ' '))
+	}
+	b, err := ApplySes("if a {\n\tb()\n}", diff.Ses())
+	assert(t, err == nil && b == "if a {\n    b()\n}")
+
+	// a tab is equal to four spaces
+	diff = New("\treturn", "    return")
+	diff.IgnoreWhitespace()
+	assert(t, diff.Editdistance() == 0)
+	assert(t, diff.SprintSes() == "- \t\n+  \n+  \n+  \n+  \n  r\n  e\n  t\n  u\n  r\n  n\n")
+
+	// identical whitespaces at the beginning of runs are common, and line breaks are not collapsed
+	diff = New("a  b\n\nc", "a    b\nc")
+	diff.IgnoreWhitespace()
+	assert(t, diff.Editdistance() == 1)
+	b, err = ApplySes("a  b\n\nc", diff.Ses())
+	assert(t, err == nil && b == "a    b\nc")
+	assert(t, diff.LcsString() == "a  b\nc")
+
+	diff = New("a\tB", "A b")
+	diff.IgnoreWhitespace()
+	diff.IgnoreCase()
+	assert(t, diff.Editdistance() == 0)
+}
+
+func TestDiffPath(t *testing.T) {
+	diff := New("abc", "abd")
+	path := diff.Path()
+	assert(t, len(path) == 3)
+	assert(t, path[0] == Point{X: 2, Y: 2})
+	assert(t, path[1] == Point{X: 2, Y: 3})
+	assert(t, path[2] == Point{X: 3, Y: 3})
+	path[0].X = 100
+	assert(t, diff.Path()[0] == Point{X: 2, Y: 2})
+
+	diff = New("abc", "abd")
+	diff.OnlyEd()
+	assert(t, diff.Path() == nil)
+}
+
+func TestDiffNoSwap(t *testing.T) {
+	diff := New("abc", "b")
+	diff.Compose()
+	assert(t, equalsSesElemOfArray(diff.Ses(), []SesElem{
+		{V: 'a', T: SesDelete},
+		{V: 'b', T: SesCommon},
+		{V: 'c', T: SesDelete},
+	}))
+
+	diff = New("abcd", "acbd")
+	diff.NoSwap()
+	diff.Compose()
+	sesExpected := []SesElem{
+		{V: 'a', T: SesCommon},
+		{V: 'c', T: SesAdd},
+		{V: 'b', T: SesCommon},
+		{V: 'c', T: SesDelete},
+		{V: 'd', T: SesCommon},
+	}
+	assert(t, diff.Editdistance() == 2)
+	assert(t, equalsSesElemOfArray(diff.Ses(), sesExpected))
+
+	for _, c := range []struct{ a, b string }{{"abcdef", "dacfea"}, {"acebdabbabed", "acbdeacbed"}, {"abc", ""}} {
+		diff = New(c.a, c.b)
+		diff.NoSwap()
+		diff.Compose()
+		assert(t, diff.Editdistance() == New(c.a, c.b).Editdistance())
+		b, err := ApplySes(c.a, diff.Ses())
+		assert(t, err == nil && b == c.b)
+	}
+}
+
+func TestDiffMaxEd(t *testing.T) {
+	diff := New("abcdef", "dacfea")
+	diff.MaxEd(5)
+	diff.Compose()
+	assert(t, diff.Editdistance() == -1)
+	assert(t, len(diff.Ses()) == 0)
+	assert(t, diff.LcsString() == "")
+
+	diff = New("abcdef", "dacfea")
+	diff.MaxEd(6)
+	diff.Compose()
+	assert(t, diff.Editdistance() == 6)
+	assert(t, diff.LcsString() == "acf")
+
+	diff = New("abcdef", "a")
+	diff.MaxEd(4)
+	assert(t, diff.Editdistance() == -1)
+}
+
+func TestDiffComposeContext(t *testing.T) {
+	diff := New("abc", "abd")
+	assert(t, diff.ComposeContext(context.Background()) == nil)
+	assert(t, diff.Editdistance() == 2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	diff = New("abc", "abd")
+	assert(t, diff.ComposeContext(ctx) == context.Canceled)
+	assert(t, diff.Editdistance() == 2)
+}
+
+func TestDiffCommonAffixes(t *testing.T) {
+	diff := New("abcxdef", "abcydef")
+	diff.Compose()
+	sesExpected := []SesElem{
+		{V: 'a', T: SesCommon, AIndex: 0, BIndex: 0},
+		{V: 'b', T: SesCommon, AIndex: 1, BIndex: 1},
+		{V: 'c', T: SesCommon, AIndex: 2, BIndex: 2},
+		{V: 'x', T: SesDelete, AIndex: 3, BIndex: -1},
+		{V: 'y', T: SesAdd, AIndex: -1, BIndex: 3},
+		{V: 'd', T: SesCommon, AIndex: 4, BIndex: 4},
+		{V: 'e', T: SesCommon, AIndex: 5, BIndex: 5},
+		{V: 'f', T: SesCommon, AIndex: 6, BIndex: 6},
+	}
+	ses := diff.Ses()
+	assert(t, len(ses) == len(sesExpected))
+	for i := range sesExpected {
+		assert(t, ses[i] == sesExpected[i])
+	}
+
+	// trimming common suffix does not change how a and b are aligned
+	diff = New("xab", "abyab")
+	untrimmed := New("xab", "abyab")
+	epc, _ := untrimmed.searchPath(context.Background())
+	untrimmed.recordResult(nil, epc)
+	assert(t, diff.Editdistance() == 4)
+	assert(t, diff.SprintSes() == "- x\n  a\n  b\n+ y\n+ a\n+ b\n")
+	assert(t, equalsSesElemOfArray(diff.Ses(), untrimmed.ses))
+	stepped := New("xab", "abyab")
+	for !stepped.Step() {
+	}
+	assert(t, stepped.SprintSes() == diff.SprintSes())
+
+	diff = New("abcxdef", "abcydef")
+	diff.OnlyEd()
+	assert(t, diff.Editdistance() == 2)
+	diff = New("abc", "abc")
+	diff.OnlyEd()
+	assert(t, diff.Editdistance() == 0)
+}
+
+func TestDiffOnlyEdWithoutPath(t *testing.T) {
+	diff := New("abcdef", "dacfea")
+	diff.OnlyEd()
+	diff.Compose()
+	assert(t, diff.Editdistance() == 6)
+	assert(t, diff.path == nil)
+	assert(t, diff.pointWithRoute == nil)
+}
+
+func TestDiffLcsIndices(t *testing.T) {
+	diff := New("abcdef", "dacfea")
+	pairs := diff.LcsIndices()
+	assert(t, len(pairs) == 3)
+	assert(t, pairs[0] == IndexPair{A: 0, B: 1})
+	assert(t, pairs[1] == IndexPair{A: 2, B: 2})
+	assert(t, pairs[2] == IndexPair{A: 5, B: 3})
+}
+
+func TestDiffRunes(t *testing.T) {
+	diff := NewRunes([]rune("久保竜彦"), []rune("久保達彦"))
+	assert(t, diff.Editdistance() == 2)
+	assert(t, diff.LcsString() == "久保彦")
+}
+
+func TestDiffStats(t *testing.T) {
+	diff := New("acbdeacbed", "acebdabbabed")
+	added, deleted, common := diff.Stats()
+	assert(t, added == 4 && deleted == 2 && common == 8)
+
+	diff = New("abcdef", "ab")
+	diff.OnlyEd()
+	added, deleted, common = diff.Stats()
+	assert(t, added == 0 && deleted == 4 && common == 2)
+}
+
+func TestDiffFastPath(t *testing.T) {
+	diff := New("abc", "abc")
+	assert(t, diff.Editdistance() == 0)
+	assert(t, diff.LcsString() == "abc")
+	assert(t, len(diff.Path()) == 1)
+	assert(t, diff.Path()[0] == Point{X: 3, Y: 3})
+
+	diff = New("", "abc")
+	assert(t, diff.Editdistance() == 3)
+	assert(t, diff.SprintSes() == "+ a\n+ b\n+ c\n")
+	assert(t, len(diff.Path()) == 4)
+
+	diff = New("abc", "")
+	diff.NoSwap()
+	assert(t, diff.Editdistance() == 3)
+	assert(t, diff.SprintSes() == "- a\n- b\n- c\n")
+
+	diff = New("abc", "")
+	diff.MaxEd(2)
+	assert(t, diff.Editdistance() == -1)
+}
+
+func TestDiffReset(t *testing.T) {
+	diff := New("abc", "abd")
+	diff.IgnoreCase()
+	assert(t, diff.Editdistance() == 2)
+
+	diff.Reset("kitten", "SITTING")
+	assert(t, diff.Editdistance() == 5)
+	assert(t, diff.LcsString() == "ittn")
+	fresh := New("kitten", "SITTING")
+	fresh.IgnoreCase()
+	assert(t, diff.SprintSes() == fresh.SprintSes())
+
+	diff.Reset("", "")
+	assert(t, diff.Editdistance() == 0)
+	assert(t, len(diff.Ses()) == 0)
+
+	diff = New("abc", "a")
+	diff.NoSwap()
+	diff.Reset("xyz", "x")
+	assert(t, diff.SprintSes() == "  x\n- y\n- z\n")
+}
+
+func TestDiffSetEqual(t *testing.T) {
+	diff := New("id: 123, name: a", "id: 9, name: b")
+	diff.SetEqual(func(x, y rune) bool {
+		if unicode.IsDigit(x) && unicode.IsDigit(y) {
+			return true
+		}
+		return x == y
+	})
+	assert(t, diff.Editdistance() == 4)
+	assert(t, diff.LcsString() == "id: 1, name: ")
+
+	diff = New("ABC", "abd")
+	diff.IgnoreCase()
+	diff.SetEqual(func(x, y rune) bool { return x == y })
+	assert(t, diff.Editdistance() == 6)
+}
+
+func TestSesTypeString(t *testing.T) {
+	assert(t, SesDelete.String() == "Delete")
+	assert(t, SesCommon.String() == "Common")
+	assert(t, SesAdd.String() == "Add")
+	assert(t, SesMove.String() == "Move")
+	assert(t, SesReplace.String() == "Replace")
+	assert(t, SesType(10).String() == "SesType(10)")
+}
+
+func TestSesElemString(t *testing.T) {
+	ses := New("ab", "b").Ses()
+	assert(t, ses[0].String() == "Delete 'a'")
+	assert(t, fmt.Sprint(ses[1]) == "Common 'b'")
+	assert(t, SesElemOf[string]{V: "x\n", T: SesAdd}.String() == `Add "x\n"`)
+	assert(t, SesElemOf[int]{V: 42, T: SesDelete}.String() == "Delete 42")
+}
+
+func TestDiffOnlyAddsAndDeletes(t *testing.T) {
+	diff := New("abcdef", "dacfea")
+	assert(t, equalsSesElemOfArray(diff.OnlyAdds(), []SesElem{
+		{V: 'd', T: SesAdd},
+		{V: 'e', T: SesAdd},
+		{V: 'a', T: SesAdd},
+	}))
+	assert(t, equalsSesElemOfArray(diff.OnlyDeletes(), []SesElem{
+		{V: 'b', T: SesDelete},
+		{V: 'd', T: SesDelete},
+		{V: 'e', T: SesDelete},
+	}))
+	for _, e := range diff.OnlyAdds() {
+		assert(t, e.AIndex == -1 && []rune("dacfea")[e.BIndex] == e.V)
+	}
+
+	lines := NewLines("a\nb\n", "a\nc\nd\n")
+	assert(t, len(lines.OnlyAdds()) == 2 && lines.OnlyAdds()[1].V == "d\n")
+	assert(t, len(lines.OnlyDeletes()) == 1 && lines.OnlyDeletes()[0].V == "b\n")
+}
+
+func TestCommonPrefix(t *testing.T) {
+	assert(t, commonPrefix([]rune(""), []rune("abc")) == 0)
+	assert(t, commonPrefix([]rune("abc"), []rune("abd")) == 2)
+	assert(t, commonPrefix([]rune("abcdefghijklmnopq"), []rune("abcdefghijklmnopq")) == 17)
+	assert(t, commonPrefix([]rune("abcdefghijklmnopq"), []rune("abcdefghijXlmnopq")) == 10)
+	assert(t, commonPrefix([]byte("abcdefgh"), []byte("abcdefghi")) == 8)
+}
+
+func TestDiffChunkedSnake(t *testing.T) {
+	common := strings.Repeat("abcdefghij", 10)
+	pairs := [][2]string{
+		{common + "x" + common, common + "y" + common},
+		{"x" + common + "yz", common + "y" + common},
+		{"acbdeacbed" + common, "acebdabbabed" + common + "!"},
+	}
+	for _, pair := range pairs {
+		diff := New(pair[0], pair[1])
+		slow := New(pair[0], pair[1])
+		slow.SetEqual(equalRune)
+		assert(t, diff.Editdistance() == slow.Editdistance())
+		assert(t, diff.SprintSes() == slow.SprintSes())
+	}
+}
+
+func benchmarkLongSnakes(b *testing.B, elementwise bool) {
+	common := []rune(strings.Repeat("abcdefghij", 100000))
+	x := append(append([]rune("x"), common...), 'x')
+	y := append(append([]rune("y"), common...), 'y')
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		diff := NewRunes(x, y)
+		if elementwise {
+			diff.SetEqual(equalRune)
+		}
+		diff.OnlyEd()
+		diff.Compose()
+	}
+}
+
+func BenchmarkDiffLongSnakes(b *testing.B) {
+	benchmarkLongSnakes(b, false)
+}
+
+func BenchmarkDiffLongSnakesElementwise(b *testing.B) {
+	benchmarkLongSnakes(b, true)
+}
+
+func TestDiffReversed(t *testing.T) {
+	assert(t, !New("abc", "abcd").Reversed())
+	assert(t, New("abcd", "abc").Reversed())
+	assert(t, New("abc", "abd").Reversed())
+
+	diff := New("abcd", "ab")
+	diff.NoSwap()
+	assert(t, !diff.Reversed())
+
+	diff = New("abcd", "xb")
+	path := diff.Path()
+	end := path[len(path)-1]
+	if diff.Reversed() {
+		end.X, end.Y = end.Y, end.X
+	}
+	assert(t, end == Point{X: 4, Y: 2})
+}
+
+func TestExceedsMaxInputLength(t *testing.T) {
+	assert(t, !exceedsMaxInputLength(0, 0))
+	assert(t, !exceedsMaxInputLength(MaxInputLength, 0))
+	assert(t, !exceedsMaxInputLength(MaxInputLength/2, MaxInputLength-MaxInputLength/2))
+	assert(t, exceedsMaxInputLength(MaxInputLength, 1))
+	assert(t, exceedsMaxInputLength(math.MaxInt, math.MaxInt))
+}
+
+func TestResetExceedsMaxInputLength(t *testing.T) {
+	// elements of zero size are not allocated however many they are
+	diff := NewSlice([]struct{}{}, []struct{}{}, func(x, y struct{}) bool { return true })
+	defer func() {
+		assert(t, recover() != nil)
+	}()
+	diff.Reset(make([]struct{}, MaxInputLength), make([]struct{}, 1))
+}
+
+type version struct {
+	major, minor int
+	label        string
+}
+
+func (v version) Equal(other version) bool {
+	return v.major == other.major && v.minor == other.minor
+}
+
+func TestNewSliceEqualer(t *testing.T) {
+	a := []version{{1, 0, "a"}, {1, 1, "b"}, {2, 0, "c"}}
+	b := []version{{1, 0, "x"}, {2, 0, "y"}, {3, 0, "z"}}
+	diff := NewSlice(a, b, nil)
+	assert(t, diff.Editdistance() == 2)
+	lcs := diff.Lcs()
+	assert(t, len(lcs) == 2 && lcs[0].label == "a" && lcs[1].label == "c")
+
+	// Equaler takes precedence over eq
+	diff = NewSlice(a, b, func(x, y version) bool { return x == y })
+	assert(t, diff.Editdistance() == 2)
+
+	// Equaler implemented by pointers is detected as well
+	pointers := NewSlice([]*node{{1}, {2}}, []*node{{2}}, nil)
+	assert(t, pointers.Editdistance() == 1)
+
+	// Equaler is detected for each value of an interface type, falling back to eq for the others
+	values := NewInterface([]interface{}{key{1, "a"}, 1}, []interface{}{key{1, "x"}, 1, 2}, func(x, y interface{}) bool {
+		return x == y
+	})
+	assert(t, values.Editdistance() == 1)
+	shapes := NewSlice([]shape{square{1}, square{2}}, []shape{square{2}}, nil)
+	assert(t, shapes.Editdistance() == 1)
+}
+
+type key struct {
+	id    int
+	label string
+}
+
+func (k key) Equal(other interface{}) bool {
+	o, ok := other.(key)
+	return ok && k.id == o.id
+}
+
+type shape interface {
+	Equal(other shape) bool
+}
+
+type square struct {
+	side int
+}
+
+func (s square) Equal(other shape) bool {
+	o, ok := other.(square)
+	return ok && s.side == o.side
+}
+
+type node struct {
+	id int
+}
+
+func (n *node) Equal(other *node) bool {
+	return n.id == other.id
+}
+
+func TestNewSliceWithoutEqualer(t *testing.T) {
+	defer func() {
+		assert(t, recover() != nil)
+	}()
+	NewSlice([]int{1}, []int{1}, nil)
+}
+
+func TestDiffOnProgress(t *testing.T) {
+	diff := New("acbdeacbed", "acebdabbabed")
+	ps := make([]int, 0)
+	diff.OnProgress(func(p int) {
+		ps = append(ps, p)
+	})
+	diff.Compose()
+	// edit distance is |M-N|+2P where P is the last p
+	assert(t, len(ps) > 0)
+	for i, p := range ps {
+		assert(t, p == i)
+	}
+	assert(t, diff.Editdistance() == 2+2*ps[len(ps)-1])
+}
+
+func TestDiffEqual(t *testing.T) {
+	assert(t, New("", "").Equal())
+	assert(t, New("abc", "abc").Equal())
+	assert(t, !New("abc", "abd").Equal())
+	assert(t, !New("abc", "abcd").Equal())
+
+	diff := New("Hello World", "hello world")
+	assert(t, !diff.Equal())
+	diff.IgnoreCase()
+	assert(t, diff.Equal())
+
+	diff = New("a b", "a\tb")
+	diff.IgnoreWhitespace()
+	assert(t, diff.Equal())
+
+	diff = New("abc", "abd")
+	diff.Compose()
+	assert(t, !diff.Equal())
+	diff = New("abc", "")
+	diff.MaxEd(1)
+	diff.Compose()
+	assert(t, !diff.Equal())
+
+	assert(t, NewLines("a\nb\n", "a\nb\n").Equal())
+
+	// Equal agrees with edit distance of anchored or ignoring diffs
+	anchored, err := NewAnchored("abab", "abab", []AnchorPair{{A: 0, B: 2, Len: 2}})
+	assert(t, err == nil)
+	assert(t, !anchored.Equal())
+	assert(t, anchored.Editdistance() == 4)
+	for _, pair := range [][2]string{{"a b", "a b"}, {"a b", "ab"}, {"a  b", "a b"}, {"ab", "ab "}} {
+		diff := New(pair[0], pair[1])
+		diff.IgnoreRunes(" ")
+		expected := New(pair[0], pair[1])
+		expected.IgnoreRunes(" ")
+		assert(t, diff.Equal() == (expected.Editdistance() == 0))
+	}
+}
+
+func TestDiffFrontiers(t *testing.T) {
+	diff := New("ab", "ab")
+	assert(t, diff.Frontiers() == nil)
+
+	diff = New("ab", "ab")
+	diff.RecordFrontiers()
+	frontiers := diff.Frontiers()
+	assert(t, len(frontiers) == 1)
+	assert(t, equalsIntArray(frontiers[0], []int{-1, -1, -1, 2, -1, -1, -1}))
+
+	diff = New("acbdeacbed", "acebdabbabed")
+	diff.RecordFrontiers()
+	frontiers = diff.Frontiers()
+	m, delta := 10, 2
+	assert(t, diff.Editdistance() == 6)
+	assert(t, len(frontiers) == 3)
+	assert(t, frontiers[len(frontiers)-1][delta+m+1] == 12)
+	for _, fp := range frontiers[:len(frontiers)-1] {
+		assert(t, fp[delta+m+1] < 12)
+	}
+	undiagnosed := New("acbdeacbed", "acebdabbabed")
+	assert(t, diff.SprintSes() == undiagnosed.SprintSes())
+}
+
+func equalsIntArray(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+var emptyPermutations = [][2]string{{"", ""}, {"", "abc"}, {"abc", ""}}
+
+func TestEmptyInputs(t *testing.T) {
+	for _, pair := range emptyPermutations {
+		a, b := pair[0], pair[1]
+		ed := len(a) + len(b)
+		diff := New(a, b)
+		assert(t, diff.Editdistance() == ed)
+		assert(t, len(diff.Lcs()) == 0)
+		assert(t, len(diff.Ses()) == ed)
+		for _, e := range diff.Ses() {
+			if a == "" {
+				assert(t, e.T == SesAdd)
+			} else {
+				assert(t, e.T == SesDelete)
+			}
+		}
+		result, err := ApplySes(a, diff.Ses())
+		assert(t, err == nil && result == b)
+
+		bytesDiff := NewBytes([]byte(a), []byte(b))
+		assert(t, bytesDiff.Editdistance() == ed)
+		assert(t, len(bytesDiff.Ses()) == ed)
+
+		lines := NewLines(a, b)
+		assert(t, lines.Editdistance() == min(ed, 1))
+		assert(t, (lines.UnifiedDiff("a", "b", 3) == "") == (ed == 0))
+
+		readers, err := NewReaders(strings.NewReader(a), strings.NewReader(b))
+		assert(t, err == nil && readers.Editdistance() == ed)
+
+		for _, tokens := range []*DiffOf[string]{NewWords(a, b), NewParagraphs(a, b)} {
+			assert(t, tokens.Editdistance() == min(ed, 1))
+		}
+		assert(t, NewGraphemes(a, b).Editdistance() == ed)
+
+		linear := New(a, b)
+		linear.LinearSpace()
+		assert(t, linear.Editdistance() == ed)
+		assert(t, len(linear.Ses()) == ed)
+
+		var buf bytes.Buffer
+		assert(t, diff.FprintSes(&buf) == nil)
+		assert(t, strings.Count(buf.String(), "\n") == ed)
+	}
+}
+
+func TestDiffEscapeControl(t *testing.T) {
+	diff := New("a\tb\n", "a b\x1b")
+	diff.EscapeControl()
+	assert(t, diff.SprintSes() == `  a
+- \t
++  
+  b
+- \n
++ \x1b
+`)
+
+	diff = New("\u200b", "")
+	diff.EscapeControl()
+	assert(t, diff.SprintSes() == "- \\u200b\n")
+
+	diff = New("a\tb", "ab")
+	assert(t, diff.SprintSes() == "  a\n- \t\n  b\n")
+}
+
+func TestDiffPreferDeletesFirst(t *testing.T) {
+	diff := New("abc", "axyc")
+	diff.PreferDeletesFirst()
+	assert(t, diff.SprintSes() == "  a\n- b\n+ x\n+ y\n  c\n")
+
+	diff = New("axyc", "abc")
+	diff.PreferDeletesFirst()
+	assert(t, diff.SprintSes() == "  a\n- x\n- y\n+ b\n  c\n")
+
+	diff = New("abc", "axyc")
+	diff.PreferDeletesFirst()
+	ses := make([]SesElem, 0)
+	diff.EachSes(func(e SesElem) bool {
+		ses = append(ses, e)
+		return len(ses) < 3
+	})
+	assert(t, equalsSesElemOfArray(ses, []SesElem{
+		{V: 'a', T: SesCommon},
+		{V: 'b', T: SesDelete},
+		{V: 'x', T: SesAdd},
+	}))
+
+	diff = New("abc", "xyz")
+	diff.PreferDeletesFirst()
+	diff.Limit(4)
+	assert(t, diff.SprintSes() == "- a\n- b\n- c\n+ x\n")
+}
+
+// expected outputs are generated by diff -u of GNU diffutils 3.8
+func TestLineDiffPreferDeletesFirstLikeGNUDiff(t *testing.T) {
+	tests := []struct {
+		a, b, expected string
+	}{
+		{"a\nb\nc\n", "a\nx\ny\nc\n", "--- a\n+++ b\n@@ -1,3 +1,4 @@\n a\n-b\n+x\n+y\n c\n"},
+		{"one\ntwo\nthree\nfour\n", "zero\none\n2\nthree\n4\n", "--- a\n+++ b\n@@ -1,4 +1,5 @@\n+zero\n one\n-two\n+2\n three\n-four\n+4\n"},
+		{"x\ny\nz\n", "p\n", "--- a\n+++ b\n@@ -1,3 +1 @@\n-x\n-y\n-z\n+p\n"},
+	}
+	for _, tt := range tests {
+		diff := NewLines(tt.a, tt.b)
+		diff.PreferDeletesFirst()
+		assert(t, diff.UnifiedDiff("a", "b", 3) == tt.expected)
+	}
+}
+
+func TestDiffNormalizedDistance(t *testing.T) {
+	assert(t, New("", "").NormalizedDistance() == 0)
+	assert(t, New("abc", "abc").NormalizedDistance() == 0)
+	assert(t, New("abc", "xyz").NormalizedDistance() == 1)
+	assert(t, New("abc", "").NormalizedDistance() == 1)
+	assert(t, New("abcd", "abce").NormalizedDistance() == 0.25)
+	diff := New("kitten", "sitting")
+	assert(t, math.Abs(diff.NormalizedDistance()+diff.Ratio()-1) < 1e-12)
+
+	diff = New("abc", "xyz")
+	diff.MaxEd(2)
+	assert(t, diff.NormalizedDistance() == 1)
+}
+
+func TestDiffSetKey(t *testing.T) {
+	type user struct {
+		id   int
+		name string
+	}
+	a := []user{{1, "alice"}, {2, "bob"}, {3, "carol"}}
+	b := []user{{1, "alice"}, {3, "carol"}, {4, "dave"}}
+	diff := NewSlice(a, b, func(x, y user) bool { return x == y })
+	diff.SetKey(func(u user) string { return fmt.Sprintf("user:%d", u.id) })
+	keys := make([]string, 0)
+	for _, e := range diff.Ses() {
+		assert(t, e.Key == fmt.Sprintf("user:%d", e.V.id))
+		keys = append(keys, e.T.String()+" "+e.Key)
+	}
+	assert(t, strings.Join(keys, ",") == "Common user:1,Delete user:2,Common user:3,Add user:4")
+
+	for _, e := range New("ab", "b").Ses() {
+		assert(t, e.Key == "")
+	}
+}
+
+func TestDiffBytesAddedDeleted(t *testing.T) {
+	diff := New("a久保竜彦", "久保達彦\U0001F600!")
+	assert(t, diff.BytesAdded() == 3+4+1)
+	assert(t, diff.BytesDeleted() == 1+3)
+	added, deleted, _ := diff.Stats()
+	assert(t, added == 3 && deleted == 2)
+
+	diff = New("", "")
+	assert(t, diff.BytesAdded() == 0 && diff.BytesDeleted() == 0)
+}
+
+func TestDiffWithinDistance(t *testing.T) {
+	diff := New("acbdeacbed", "acebdabbabed")
+	assert(t, !diff.WithinDistance(5))
+	assert(t, diff.WithinDistance(6))
+	assert(t, diff.WithinDistance(100))
+	// the difference of the lengths exceeds tolerance
+	assert(t, !New("a", "abcd").WithinDistance(2))
+	assert(t, New("", "").WithinDistance(0))
+	assert(t, !New("abc", "abd").WithinDistance(-1))
+
+	diff.Compose()
+	assert(t, !diff.WithinDistance(5))
+	assert(t, diff.WithinDistance(6))
+
+	diff = New("abc", "xyz")
+	diff.MaxEd(2)
+	diff.Compose()
+	assert(t, diff.WithinDistance(6))
+	assert(t, !diff.WithinDistance(5))
+
+	// the search goes through anchors
+	anchored, err := NewAnchored("abc", "bca", []AnchorPair{{A: 0, B: 2, Len: 1}})
+	assert(t, err == nil)
+	assert(t, !anchored.WithinDistance(2))
+	assert(t, anchored.WithinDistance(4))
+	assert(t, anchored.Editdistance() == 4)
+}
+
+func TestDiffLcsLength(t *testing.T) {
+	pairs := [][2]string{{"", ""}, {"abc", ""}, {"abc", "abd"}, {"kitten", "sitting"}, {"acbdeacbed", "acebdabbabed"}, {"久保竜彦", "久保達彦"}}
+	for _, pair := range pairs {
+		diff := New(pair[0], pair[1])
+		onlyEd := New(pair[0], pair[1])
+		onlyEd.OnlyEd()
+		assert(t, diff.LcsLength() == len(diff.Lcs()))
+		assert(t, onlyEd.LcsLength() == len(diff.Lcs()))
+		assert(t, onlyEd.Lcs() == nil)
+	}
+	diff := New("abc", "xyz")
+	diff.MaxEd(1)
+	assert(t, diff.LcsLength() == -1)
+}
+
+func FuzzDiff(f *testing.F) {
+	for _, pair := range [][2]string{{"", ""}, {"abc", "abd"}, {"acbdeacbed", "acebdabbabed"}, {"久保竜彦", "久保達彦"}, {"a\nb\n", "b\nc"}} {
+		f.Add(pair[0], pair[1])
+	}
+	f.Fuzz(func(t *testing.T, a, b string) {
+		if !utf8.ValidString(a) || !utf8.ValidString(b) {
+			t.Skip()
+		}
+		diff := New(a, b)
+		diff.Compose()
+		ses := diff.Ses()
+		s, err := ApplySes(a, ses)
+		if err != nil || s != b {
+			t.Fatalf("ApplySes(%q, ses) = %q, %v, want %q", a, s, err, b)
+		}
+		ed, x, y := 0, 0, 0
+		for _, e := range ses {
+			switch e.T {
+			case SesDelete:
+				if e.AIndex != x || e.BIndex != -1 {
+					t.Fatalf("%v has indices (%d, %d), want (%d, -1)", e, e.AIndex, e.BIndex, x)
+				}
+				ed++
+				x++
+			case SesAdd:
+				if e.AIndex != -1 || e.BIndex != y {
+					t.Fatalf("%v has indices (%d, %d), want (-1, %d)", e, e.AIndex, e.BIndex, y)
+				}
+				ed++
+				y++
+			case SesCommon:
+				if e.AIndex != x || e.BIndex != y {
+					t.Fatalf("%v has indices (%d, %d), want (%d, %d)", e, e.AIndex, e.BIndex, x, y)
+				}
+				x++
+				y++
+			}
+		}
+		if ed != diff.Editdistance() {
+			t.Fatalf("edit distance is %d, but SES has %d edits", diff.Editdistance(), ed)
+		}
+		if inverted := New(b, a); inverted.Editdistance() != ed {
+			t.Fatalf("edit distance from b to a is %d, want %d", inverted.Editdistance(), ed)
+		}
+		onlyEd := New(a, b)
+		onlyEd.OnlyEd()
+		if onlyEd.Editdistance() != ed {
+			t.Fatalf("edit distance with OnlyEd is %d, want %d", onlyEd.Editdistance(), ed)
+		}
+	})
+}
@@ -0,0 +1,67 @@
+package gonp
+
+import (
+	"unicode"
+)
+
+// NewGraphemes is initializer of DiffOf comparing a and b by grapheme clusters.
+// Clusters are approximation of extended grapheme clusters of Unicode: a character
+// with following combining marks, variation selectors and emoji modifiers,
+// emoji sequences joined by ZWJ, pairs of regional indicators and CR LF
+func NewGraphemes(a, b string) *DiffOf[string] {
+	return NewTokens(a, b, GraphemeTokenizer)
+}
+
+const zwj = '\u200d'
+
+func splitGraphemes(s string) []string {
+	clusters := make([]string, 0, len(s))
+	start := 0
+	prev := rune(-1)
+	regionals := 0
+	for i, r := range s {
+		if i > start && !continuesGrapheme(prev, r, regionals) {
+			clusters = append(clusters, s[start:i])
+			start = i
+			regionals = 0
+		}
+		if isRegionalIndicator(r) {
+			regionals++
+		}
+		prev = r
+	}
+	if start < len(s) {
+		clusters = append(clusters, s[start:])
+	}
+	return clusters
+}
+
+// continuesGrapheme reports whether r belongs to the same cluster as prev.
+// regionals is the number of regional indicators in the cluster
+func continuesGrapheme(prev, r rune, regionals int) bool {
+	switch {
+	case prev == '\r':
+		return r == '\n'
+	case unicode.IsControl(prev) || unicode.IsControl(r):
+		return false
+	case isGraphemeExtend(r):
+		return true
+	case prev == zwj:
+		return true
+	case isRegionalIndicator(prev) && isRegionalIndicator(r):
+		return regionals%2 == 1
+	}
+	return false
+}
+
+func isGraphemeExtend(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc) ||
+		r == zwj ||
+		unicode.Is(unicode.Variation_Selector, r) ||
+		(r >= 0x1f3fb && r <= 0x1f3ff) ||
+		(r >= 0xe0020 && r <= 0xe007f)
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
@@ -0,0 +1,41 @@
+package gonp
+
+import (
+	"testing"
+)
+
+func TestSplitGraphemes(t *testing.T) {
+	s := "e\u0301a\r\n\U0001f1ef\U0001f1f5\U0001f1fa\U0001f1f8\U0001f468\u200d\U0001f469\u200d\U0001f467\U0001f44d\U0001f3fd\u2764\ufe0f"
+	expected := []string{
+		"e\u0301",
+		"a",
+		"\r\n",
+		"\U0001f1ef\U0001f1f5",
+		"\U0001f1fa\U0001f1f8",
+		"\U0001f468\u200d\U0001f469\u200d\U0001f467",
+		"\U0001f44d\U0001f3fd",
+		"\u2764\ufe0f",
+	}
+	clusters := splitGraphemes(s)
+	assert(t, len(clusters) == len(expected))
+	for i := range expected {
+		assert(t, clusters[i] == expected[i])
+	}
+	assert(t, len(splitGraphemes("")) == 0)
+}
+
+func TestDiffGraphemes(t *testing.T) {
+	diff := NewGraphemes("flag \U0001f1ef\U0001f1f5", "flag \U0001f1fa\U0001f1f8")
+	diff.Compose()
+	sesExpected := []SesElemOf[string]{
+		{V: "f", T: SesCommon},
+		{V: "l", T: SesCommon},
+		{V: "a", T: SesCommon},
+		{V: "g", T: SesCommon},
+		{V: " ", T: SesCommon},
+		{V: "\U0001f1ef\U0001f1f5", T: SesDelete},
+		{V: "\U0001f1fa\U0001f1f8", T: SesAdd},
+	}
+	assert(t, diff.Editdistance() == 2)
+	assert(t, equalsSesElemOfArray(diff.Ses(), sesExpected))
+}
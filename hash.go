@@ -0,0 +1,56 @@
+package gonp
+
+// SetHash makes elements of a and b compared by their hashes calculated by fn before eq,
+// so eq is called only for elements with the same hash. It speeds up comparing long elements
+// like lines, which are compared repeatedly. fn must return the same hash for elements equal by eq.
+// Hashes are cleared when the comparison is changed by SetEqual, IgnoreCase, IgnoreWhitespace
+// or NormalizeLineEndings, since they may not be consistent with the new comparison
+func (diff *DiffOf[T]) SetHash(fn func(v T) uint64) {
+	diff.hash = fn
+	diff.rehash()
+}
+
+// rehash calculates hashes of elements of a and b, reusing the buffers
+func (diff *DiffOf[T]) rehash() {
+	if diff.hash == nil {
+		diff.hashA, diff.hashB = nil, nil
+		return
+	}
+	diff.hashA = hashAll(resize(diff.hashA, diff.m), diff.a, diff.hash)
+	diff.hashB = hashAll(resize(diff.hashB, diff.n), diff.b, diff.hash)
+}
+
+func (diff *DiffOf[T]) clearHash() {
+	diff.hash = nil
+	diff.rehash()
+}
+
+func hashAll[T any](hashes []uint64, s []T, fn func(v T) uint64) []uint64 {
+	for i, v := range s {
+		hashes[i] = fn(v)
+	}
+	return hashes
+}
+
+// equalAt reports whether a[x] and b[y] are equal, comparing their hashes first if any
+func (diff *DiffOf[T]) equalAt(x, y int) bool {
+	if diff.hashA != nil && diff.hashA[x] != diff.hashB[y] {
+		return false
+	}
+	return diff.eq(diff.a[x], diff.b[y])
+}
+
+const (
+	fnvOffset64 = 14695981039346656037
+	fnvPrime64  = 1099511628211
+)
+
+// hashString returns FNV-1a hash of s without allocation
+func hashString(s string) uint64 {
+	h := uint64(fnvOffset64)
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= fnvPrime64
+	}
+	return h
+}
@@ -0,0 +1,100 @@
+package gonp
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestDiffSetHash(t *testing.T) {
+	a := []string{"alpha", "beta", "gamma", "delta"}
+	b := []string{"alpha", "gamma", "delta", "epsilon"}
+	expected := NewStringSlice(a, b)
+	expected.Compose()
+
+	calls := 0
+	diff := NewStringSlice(a, b)
+	diff.SetEqual(func(x, y string) bool {
+		calls++
+		return x == y
+	})
+	diff.SetHash(hashString)
+	diff.Compose()
+	assert(t, diff.Editdistance() == expected.Editdistance())
+	assert(t, equalsSesElemOfArray(diff.Ses(), expected.Ses()))
+	// eq is called only for elements with the same hash, that is, common elements here
+	assert(t, calls == len(diff.Lcs()))
+}
+
+func TestDiffSetHashCollision(t *testing.T) {
+	// every element has the same hash, so eq decides
+	diff := NewStringSlice([]string{"a", "b", "c"}, []string{"a", "x", "c"})
+	diff.SetHash(func(v string) uint64 { return 0 })
+	diff.Compose()
+	assert(t, diff.Editdistance() == 2)
+	assert(t, len(diff.Lcs()) == 2)
+}
+
+func TestDiffSetHashCleared(t *testing.T) {
+	diff := NewLines("a\r\nb\n", "a\nb\n")
+	diff.NormalizeLineEndings()
+	assert(t, diff.Editdistance() == 0)
+
+	diff = NewLines("a\nB\n", "a\nb\n")
+	diff.SetEqual(strings.EqualFold)
+	assert(t, diff.Editdistance() == 0)
+}
+
+func TestDiffSetHashOptions(t *testing.T) {
+	a := "a\nb\nc\nd\ne\nf\n"
+	b := "a\nc\nd\nx\nf\ng\n"
+	expected := NewLines(a, b)
+	expected.Compose()
+	for _, configure := range []func(*LineDiff){
+		func(diff *LineDiff) { diff.NoSwap() },
+		func(diff *LineDiff) { diff.LinearSpace() },
+		func(diff *LineDiff) { diff.AlignSuffix() },
+		func(diff *LineDiff) { diff.OnlyEd() },
+	} {
+		diff := NewLines(a, b)
+		configure(diff)
+		plain := NewLines(a, b)
+		plain.clearHash()
+		configure(plain)
+		diff.Compose()
+		plain.Compose()
+		assert(t, diff.Editdistance() == plain.Editdistance())
+		assert(t, equalsSesElemOfArray(diff.Ses(), plain.Ses()))
+	}
+}
+
+// similarLines returns n lines sharing a long prefix, where every 5th line differs between a and b
+func similarLines(n int) (string, string) {
+	var a, b strings.Builder
+	prefix := strings.Repeat("2006-01-02T15:04:05 INFO request handled ", 24)
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&a, "%s%08d\n", prefix, i%50)
+		if i%5 == 0 {
+			fmt.Fprintf(&b, "%s%08d!\n", prefix, i%50)
+		} else {
+			fmt.Fprintf(&b, "%s%08d\n", prefix, i%50)
+		}
+	}
+	return a.String(), b.String()
+}
+
+func BenchmarkDiffLinesWithoutHash(b *testing.B) {
+	x, y := similarLines(5000)
+	for i := 0; i < b.N; i++ {
+		diff := NewStringSlice(splitLines(x), splitLines(y))
+		diff.Compose()
+	}
+}
+
+func BenchmarkDiffLinesWithHash(b *testing.B) {
+	x, y := similarLines(5000)
+	for i := 0; i < b.N; i++ {
+		diff := NewLines(x, y)
+		diff.Compose()
+	}
+}
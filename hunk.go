@@ -0,0 +1,36 @@
+package gonp
+
+// HunkOf is a run of consecutive SES elements of the same type.
+// AStart and BStart are positions in a and b where the run starts
+type HunkOf[T any] struct {
+	Type   SesType
+	Elems  []SesElemOf[T]
+	AStart int
+	BStart int
+}
+
+// Hunk is a run of consecutive SES elements of the same type between strings
+type Hunk = HunkOf[rune]
+
+// Hunks groups SES between a and b into runs of the same type
+func (diff *DiffOf[T]) Hunks() []HunkOf[T] {
+	ses := diff.Ses()
+	hunks := make([]HunkOf[T], 0)
+	a, b := 0, 0
+	for i := 0; i < len(ses); {
+		h := HunkOf[T]{Type: ses[i].T, AStart: a, BStart: b}
+		j := i
+		for ; j < len(ses) && ses[j].T == h.Type; j++ {
+			if h.Type != SesAdd {
+				a++
+			}
+			if h.Type != SesDelete {
+				b++
+			}
+		}
+		h.Elems = ses[i:j]
+		hunks = append(hunks, h)
+		i = j
+	}
+	return hunks
+}
@@ -0,0 +1,32 @@
+package gonp
+
+import (
+	"testing"
+)
+
+func TestHunks(t *testing.T) {
+	diff := New("abcdef", "abxyef")
+	hunks := diff.Hunks()
+	assert(t, len(hunks) == 4)
+	expected := []struct {
+		typ            SesType
+		s              string
+		aStart, bStart int
+	}{
+		{SesCommon, "ab", 0, 0},
+		{SesDelete, "cd", 2, 2},
+		{SesAdd, "xy", 4, 2},
+		{SesCommon, "ef", 4, 4},
+	}
+	for i, e := range expected {
+		h := hunks[i]
+		s := make([]rune, 0)
+		for _, el := range h.Elems {
+			s = append(s, el.V)
+		}
+		assert(t, h.Type == e.typ)
+		assert(t, string(s) == e.s)
+		assert(t, h.AStart == e.aStart && h.BStart == e.bStart)
+	}
+	assert(t, len(New("", "").Hunks()) == 0)
+}
@@ -0,0 +1,100 @@
+package gonp
+
+import (
+	"context"
+	"strings"
+)
+
+// IgnoreRunes enables to ignore the characters in set when matching a and b.
+// They are removed from a and b to search the path, and put back into SES at their positions,
+// as common characters when the same ones are between matched characters on both sides,
+// otherwise as deletions and additions. Edit distance counts them as SES does
+func (diff *Diff) IgnoreRunes(set string) {
+	diff.ignore = func(r rune) bool {
+		return strings.ContainsRune(set, r)
+	}
+}
+
+// keptIndices returns the indices of elements of s which are not ignored
+func (diff *DiffOf[T]) keptIndices(s []T) ([]T, []int) {
+	kept := make([]T, 0, len(s))
+	indices := make([]int, 0, len(s))
+	for i, v := range s {
+		if !diff.ignore(v) {
+			kept = append(kept, v)
+			indices = append(indices, i)
+		}
+	}
+	return kept, indices
+}
+
+// findIgnoringPath searches the path between a and b without ignored elements,
+// and returns it with ignored elements put back in reverse order
+func (diff *DiffOf[T]) findIgnoringPath(ctx context.Context) ([]Point, error) {
+	sub := new(DiffOf[T])
+	var ia, ib []int
+	sub.a, ia = diff.keptIndices(diff.a)
+	sub.b, ib = diff.keptIndices(diff.b)
+	sub.m, sub.n = len(ia), len(ib)
+	sub.eq = diff.eq
+	sub.match = diff.match
+	sub.progress = diff.progress
+	sub.work = diff.work
+	sub.linearSpace = diff.linearSpace
+	sub.fast = diff.fast
+	sub.alignSuffix = diff.alignSuffix
+	sub.blank, sub.same = diff.blank, diff.same
+	sub.limit = -1
+	sub.maxEd = -1
+	epc, err := sub.findPath(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	points := []Point{{X: 0, Y: 0}}
+	ed, x, y := sub.ed, 0, 0
+	step := func(dx, dy int) {
+		x, y = x+dx, y+dy
+		points = append(points, Point{X: x, Y: y})
+	}
+	// gap steps over ignored elements before (x1, y1), matching them from the beginning
+	gap := func(x1, y1 int) {
+		for x < x1 && y < y1 && diff.eq(diff.a[x], diff.b[y]) {
+			step(1, 1)
+		}
+		for ; x < x1; ed++ {
+			step(1, 0)
+		}
+		for ; y < y1; ed++ {
+			step(0, 1)
+		}
+	}
+	sub.recordSeq(epc, func(e SesElemOf[T]) bool {
+		switch e.T {
+		case SesCommon:
+			gap(ia[e.AIndex], ib[e.BIndex])
+			step(1, 1)
+		case SesDelete:
+			gap(ia[e.AIndex], y)
+			step(1, 0)
+		case SesAdd:
+			gap(x, ib[e.BIndex])
+			step(0, 1)
+		}
+		return true
+	})
+	gap(diff.m, diff.n)
+
+	diff.ed = ed
+	if diff.maxEd >= 0 && ed > diff.maxEd {
+		diff.ed = -1
+		return nil, nil
+	}
+	if diff.onlyEd {
+		return nil, nil
+	}
+	for i, j := 0, len(points)-1; i < j; i, j = i+1, j-1 {
+		points[i], points[j] = points[j], points[i]
+	}
+	return points, nil
+}
@@ -0,0 +1,56 @@
+package gonp
+
+import (
+	"testing"
+)
+
+func TestDiffIgnoreRunes(t *testing.T) {
+	diff := New("[1, 2, 3,]", "[1, 2, 3]")
+	diff.IgnoreRunes(",;")
+	diff.Compose()
+	assert(t, diff.Editdistance() == 1)
+	assert(t, diff.LcsString() == "[1, 2, 3]")
+	assert(t, equalsSesElemOfArray(diff.OnlyDeletes(), []SesElem{
+		{V: ',', T: SesDelete, AIndex: 8, BIndex: -1},
+	}))
+
+	// ignored characters are never matched across characters which differ
+	diff = New("a;b", "c;d")
+	diff.Compose()
+	assert(t, diff.Editdistance() == 4)
+	diff = New("a;b", "c;d")
+	diff.IgnoreRunes(";")
+	diff.Compose()
+	assert(t, diff.Editdistance() == 6)
+	assert(t, len(diff.Lcs()) == 0)
+}
+
+func TestDiffIgnoreRunesApply(t *testing.T) {
+	pairs := [][2]string{
+		{"f(a, b);", "f(a; b)"},
+		{"x;y;", "x;y"},
+		{";;;", ""},
+		{"", ",,"},
+		{"abc", "abc"},
+		{"a,b,c", "c;b;a"},
+	}
+	for _, pair := range pairs {
+		diff := NewWith(pair[0], pair[1], IgnoreRunes(",;"))
+		diff.Compose()
+		s, err := ApplySes(pair[0], diff.Ses())
+		assert(t, err == nil)
+		assert(t, s == pair[1])
+		added, deleted, _ := diff.Stats()
+		assert(t, diff.Editdistance() == added+deleted)
+
+		onlyEd := NewWith(pair[0], pair[1], IgnoreRunes(",;"), OnlyEd())
+		onlyEd.Compose()
+		assert(t, onlyEd.Editdistance() == diff.Editdistance())
+	}
+}
+
+func TestDiffIgnoreRunesMaxEd(t *testing.T) {
+	diff := NewWith("a;b", "c;d", IgnoreRunes(";"), MaxEd(5))
+	diff.Compose()
+	assert(t, diff.Editdistance() == -1)
+}
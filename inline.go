@@ -0,0 +1,100 @@
+package gonp
+
+import (
+	"strings"
+)
+
+// Span is a part of line in inline diff. Changed reports whether the part is deleted or added
+type Span struct {
+	Text    string
+	Changed bool
+}
+
+// InlineLine is a line of inline diff split into spans
+type InlineLine struct {
+	T     SesType
+	Spans []Span
+}
+
+// InlineDiff returns lines of SES with changed parts of each line.
+// Deleted and added lines between common lines are paired in order like SideBySide,
+// and each pair is split into spans by rune-level diff between them.
+// Common lines and lines without any pair consist of a single span
+func (diff *LineDiff) InlineDiff() []InlineLine {
+	ses := diff.Ses()
+	lines := make([]InlineLine, 0, len(ses))
+	i := 0
+	for i < len(ses) {
+		if ses[i].T == SesCommon {
+			lines = append(lines, InlineLine{T: SesCommon, Spans: []Span{{Text: ses[i].V}}})
+			i++
+			continue
+		}
+		dels, adds := make([]string, 0), make([]string, 0)
+		for ; i < len(ses) && ses[i].T != SesCommon; i++ {
+			if ses[i].T == SesDelete {
+				dels = append(dels, ses[i].V)
+			} else {
+				adds = append(adds, ses[i].V)
+			}
+		}
+		delLines := make([]InlineLine, len(dels))
+		addLines := make([]InlineLine, len(adds))
+		for j := range dels {
+			delLines[j] = InlineLine{T: SesDelete, Spans: []Span{{Text: dels[j], Changed: true}}}
+		}
+		for j := range adds {
+			addLines[j] = InlineLine{T: SesAdd, Spans: []Span{{Text: adds[j], Changed: true}}}
+		}
+		for j := 0; j < min(len(dels), len(adds)); j++ {
+			delLines[j].Spans, addLines[j].Spans = inlineSpans(dels[j], adds[j])
+		}
+		lines = append(lines, delLines...)
+		lines = append(lines, addLines...)
+	}
+	return lines
+}
+
+// inlineSpans splits a and b into spans by diff between their characters
+func inlineSpans(a, b string) ([]Span, []Span) {
+	var as, bs spanBuilder
+	for _, e := range New(a, b).Ses() {
+		switch e.T {
+		case SesDelete:
+			as.add(e.V, true)
+		case SesAdd:
+			bs.add(e.V, true)
+		default:
+			as.add(e.V, false)
+			bs.add(e.V, false)
+		}
+	}
+	return as.spans(), bs.spans()
+}
+
+// spanBuilder joins consecutive runes into a span while they are changed or unchanged alike
+type spanBuilder struct {
+	result  []Span
+	buf     strings.Builder
+	changed bool
+}
+
+func (sb *spanBuilder) add(r rune, changed bool) {
+	if sb.buf.Len() > 0 && changed != sb.changed {
+		sb.flush()
+	}
+	sb.changed = changed
+	sb.buf.WriteRune(r)
+}
+
+func (sb *spanBuilder) flush() {
+	sb.result = append(sb.result, Span{Text: sb.buf.String(), Changed: sb.changed})
+	sb.buf.Reset()
+}
+
+func (sb *spanBuilder) spans() []Span {
+	if sb.buf.Len() > 0 {
+		sb.flush()
+	}
+	return sb.result
+}
@@ -0,0 +1,37 @@
+package gonp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInlineDiff(t *testing.T) {
+	diff := NewLines("a\nhello world\nb\nc\n", "a\nhello gonp\nb\nx\ny\n")
+	lines := diff.InlineDiff()
+	assert(t, reflect.DeepEqual(lines, []InlineLine{
+		{T: SesCommon, Spans: []Span{{Text: "a\n"}}},
+		{T: SesDelete, Spans: []Span{{Text: "hello "}, {Text: "w", Changed: true}, {Text: "o"}, {Text: "rld", Changed: true}, {Text: "\n"}}},
+		{T: SesAdd, Spans: []Span{{Text: "hello "}, {Text: "g", Changed: true}, {Text: "o"}, {Text: "np", Changed: true}, {Text: "\n"}}},
+		{T: SesCommon, Spans: []Span{{Text: "b\n"}}},
+		{T: SesDelete, Spans: []Span{{Text: "c", Changed: true}, {Text: "\n"}}},
+		{T: SesAdd, Spans: []Span{{Text: "x", Changed: true}, {Text: "\n"}}},
+		{T: SesAdd, Spans: []Span{{Text: "y\n", Changed: true}}},
+	}))
+}
+
+func TestInlineDiffReconstruct(t *testing.T) {
+	a, b := "abc\ndef\nghi", "abd\nxyz\ndef\ngh"
+	var ra, rb string
+	for _, line := range NewLines(a, b).InlineDiff() {
+		for _, span := range line.Spans {
+			if line.T != SesAdd {
+				ra += span.Text
+			}
+			if line.T != SesDelete {
+				rb += span.Text
+			}
+		}
+	}
+	assert(t, ra == a)
+	assert(t, rb == b)
+}
@@ -0,0 +1,81 @@
+package gonp
+
+import (
+	"encoding/json"
+	"fmt"
+	"unicode/utf8"
+)
+
+// MarshalText encodes SesType as "delete", "common", "add", "move" or "replace"
+func (t SesType) MarshalText() ([]byte, error) {
+	switch t {
+	case SesDelete:
+		return []byte("delete"), nil
+	case SesCommon:
+		return []byte("common"), nil
+	case SesAdd:
+		return []byte("add"), nil
+	case SesMove:
+		return []byte("move"), nil
+	case SesReplace:
+		return []byte("replace"), nil
+	}
+	return nil, fmt.Errorf("gonp: unknown SesType %d", int(t))
+}
+
+// UnmarshalText decodes SesType from "delete", "common", "add", "move" or "replace"
+func (t *SesType) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "delete":
+		*t = SesDelete
+	case "common":
+		*t = SesCommon
+	case "add":
+		*t = SesAdd
+	case "move":
+		*t = SesMove
+	case "replace":
+		*t = SesReplace
+	default:
+		return fmt.Errorf("gonp: unknown SesType %q", text)
+	}
+	return nil
+}
+
+// sesElemJSON is JSON representation of SesElem. V is encoded as a string instead of a number
+type sesElemJSON struct {
+	V      string  `json:"v"`
+	T      SesType `json:"t"`
+	AIndex int     `json:"a_index"`
+	BIndex int     `json:"b_index"`
+}
+
+// MarshalSes returns JSON encoding of SES between a and b
+func (diff *Diff) MarshalSes() ([]byte, error) {
+	ses := diff.Ses()
+	elems := make([]sesElemJSON, len(ses))
+	for i, e := range ses {
+		elems[i] = sesElemJSON{V: string(e.V), T: e.T, AIndex: e.AIndex, BIndex: e.BIndex}
+	}
+	return json.Marshal(elems)
+}
+
+// UnmarshalSes parses SES encoded by MarshalSes
+func UnmarshalSes(data []byte) ([]SesElem, error) {
+	var elems []sesElemJSON
+	if err := json.Unmarshal(data, &elems); err != nil {
+		return nil, err
+	}
+	ses := make([]SesElem, len(elems))
+	for i, e := range elems {
+		r, size := utf8.DecodeRuneInString(e.V)
+		if size == 0 || size != len(e.V) {
+			return nil, fmt.Errorf("gonp: SES element %d must be a character: %q", i, e.V)
+		}
+		if e.T == SesReplace {
+			return nil, fmt.Errorf("gonp: SES element %d must not be replace", i)
+		}
+		ses[i] = SesElem{V: r, T: e.T, AIndex: e.AIndex, BIndex: e.BIndex}
+	}
+	return ses, nil
+}
@@ -0,0 +1,43 @@
+package gonp
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMarshalSes(t *testing.T) {
+	diff := New("ab", "ac")
+	data, err := diff.MarshalSes()
+	assert(t, err == nil)
+	expected := `[{"v":"a","t":"common","a_index":0,"b_index":0},` +
+		`{"v":"b","t":"delete","a_index":1,"b_index":-1},` +
+		`{"v":"c","t":"add","a_index":-1,"b_index":1}]`
+	assert(t, string(data) == expected)
+}
+
+func TestUnmarshalSes(t *testing.T) {
+	diff := New("久保竜彦", "久保達彦")
+	data, err := diff.MarshalSes()
+	assert(t, err == nil)
+	ses, err := UnmarshalSes(data)
+	assert(t, err == nil)
+	assert(t, len(ses) == len(diff.Ses()))
+	for i, e := range diff.Ses() {
+		assert(t, ses[i] == e)
+	}
+
+	_, err = UnmarshalSes([]byte(`[{"v":"ab","t":"add"}]`))
+	assert(t, err != nil)
+	_, err = UnmarshalSes([]byte(`[{"v":"a","t":"replace"}]`))
+	assert(t, err != nil)
+}
+
+func TestSesTypeJSON(t *testing.T) {
+	data, err := json.Marshal([]SesType{SesDelete, SesCommon, SesAdd})
+	assert(t, err == nil)
+	assert(t, string(data) == `["delete","common","add"]`)
+
+	var types []SesType
+	assert(t, json.Unmarshal([]byte(`["move","replace"]`), &types) == nil)
+	assert(t, len(types) == 2 && types[0] == SesMove && types[1] == SesReplace)
+}
@@ -0,0 +1,27 @@
+package gonp
+
+// LevenshteinDistance returns edit distance between a and b counting a substitution as 1.
+// Editdistance counts only deletions and additions, so a substitution costs 2 there.
+// Since it cannot be derived exactly from SES, it is calculated separately
+// by dynamic programming in O(MN) time and O(min(M, N)) space
+func (diff *DiffOf[T]) LevenshteinDistance() int {
+	// a is the shorter one as long as NoSwap is not enabled, though the distance is symmetric anyway
+	row := make([]int, diff.m+1)
+	for i := range row {
+		row[i] = i
+	}
+	for j := 1; j <= diff.n; j++ {
+		prev := row[0]
+		row[0] = j
+		for i := 1; i <= diff.m; i++ {
+			cur := row[i]
+			if diff.eq(diff.a[i-1], diff.b[j-1]) {
+				row[i] = prev
+			} else {
+				row[i] = min(prev, min(row[i], row[i-1])) + 1
+			}
+			prev = cur
+		}
+	}
+	return row[diff.m]
+}
@@ -0,0 +1,40 @@
+package gonp
+
+import (
+	"testing"
+)
+
+func TestLevenshteinDistance(t *testing.T) {
+	tests := []struct {
+		a, b        string
+		levenshtein int
+		ed          int
+	}{
+		{"", "", 0, 0},
+		{"", "abc", 3, 3},
+		{"abc", "", 3, 3},
+		{"abc", "abc", 0, 0},
+		{"abc", "abd", 1, 2},
+		{"kitten", "sitting", 3, 5},
+		{"abc", "xabc", 1, 1},
+		{"abcdef", "azced", 3, 5},
+		{"acbdeacbed", "acebdabbabed", 5, 6},
+	}
+	for _, tt := range tests {
+		diff := New(tt.a, tt.b)
+		assert(t, diff.LevenshteinDistance() == tt.levenshtein)
+		assert(t, New(tt.b, tt.a).LevenshteinDistance() == tt.levenshtein)
+		assert(t, diff.Editdistance() == tt.ed)
+		// every substitution is a pair of deletion and addition in SES
+		assert(t, tt.levenshtein <= tt.ed && tt.ed <= 2*tt.levenshtein)
+	}
+}
+
+func TestLevenshteinDistanceWithoutSubstitution(t *testing.T) {
+	// Editdistance equals LevenshteinDistance when there is no deletion
+	// next to an addition which can be replaced with a substitution
+	for _, pair := range [][2]string{{"abc", "xaybzc"}, {"abcdef", "acf"}, {"abc", "abc"}} {
+		diff := New(pair[0], pair[1])
+		assert(t, diff.Editdistance() == diff.LevenshteinDistance())
+	}
+}
@@ -0,0 +1,88 @@
+package gonp
+
+import (
+	"context"
+)
+
+// linearSpaceThreshold is the size of edit graph under which the path is searched
+// keeping every snake even in linear space mode
+const linearSpaceThreshold = 64
+
+// LinearSpace enables to compose SES in O(M+N) space.
+// Instead of keeping every snake in memory, the edit graph is divided recursively
+// at the point where the path crosses the middle row, and each part is searched again.
+// Edit distance is the same, but it is slower since parts of the edit graph are searched repeatedly
+func (diff *DiffOf[T]) LinearSpace() {
+	diff.linearSpace = true
+}
+
+// searchLinearPath returns the same as searchPath in O(M+N) space
+func (diff *DiffOf[T]) searchLinearPath(ctx context.Context) ([]Point, error) {
+	if min(diff.m, diff.n) <= 1 || diff.m+diff.n <= linearSpaceThreshold {
+		return diff.searchPath(ctx)
+	}
+
+	diff.cross = make([]Point, diff.m+diff.n+3)
+	diff.midY = (diff.n + 1) / 2
+	onlyEd := diff.onlyEd
+	diff.onlyEd = true
+	_, err := diff.searchPath(ctx)
+	diff.onlyEd = onlyEd
+	diff.cross = nil
+	if err != nil || diff.ed < 0 {
+		return nil, err
+	}
+
+	mid := diff.mid
+	// the path may cross the middle row in the common suffix which snakes run into
+	if mid == (Point{}) || mid == (Point{X: diff.m, Y: diff.n}) || mid.X > diff.m || mid.Y > diff.n {
+		return diff.searchPath(ctx)
+	}
+
+	head := diff.subDiff(0, mid.X, 0, mid.Y)
+	tail := diff.subDiff(mid.X, diff.m, mid.Y, diff.n)
+	diff.runIntoSuffix(tail, mid.X, mid.Y, diff.tail)
+	headEpc, err := head.searchLinearPath(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tailEpc, err := tail.searchLinearPath(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	epc := make([]Point, 0, len(headEpc)+len(tailEpc))
+	for _, p := range tailEpc {
+		epc = append(epc, Point{X: p.X + mid.X, Y: p.Y + mid.Y})
+	}
+	return append(epc, headEpc...), nil
+}
+
+// subDiff returns context for the part of edit graph between (x0, y0) and (x1, y1)
+func (diff *DiffOf[T]) subDiff(x0, x1, y0, y1 int) *DiffOf[T] {
+	sub := new(DiffOf[T])
+	sub.a, sub.b = diff.a[x0:x1], diff.b[y0:y1]
+	sub.m, sub.n = x1-x0, y1-y0
+	sub.eq = diff.eq
+	if diff.hashA != nil {
+		sub.hashA, sub.hashB = diff.hashA[x0:x1], diff.hashB[y0:y1]
+	}
+	sub.match = diff.match
+	sub.progress = diff.progress
+	sub.work = diff.work
+	sub.limit = -1
+	sub.maxEd = -1
+	return sub
+}
+
+// crossing returns the point where the path crosses the middle row.
+// c is the point of the path before the snake from (x0, y0) to (x, y), or (-1, -1) if not crossed yet
+func (diff *DiffOf[T]) crossing(c Point, x0, y0, x, y int) Point {
+	if c.Y >= 0 || y < diff.midY {
+		return c
+	}
+	if x0 == 0 && y0 == 0 {
+		return Point{X: x, Y: y}
+	}
+	return Point{X: x0, Y: y0}
+}
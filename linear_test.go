@@ -0,0 +1,33 @@
+package gonp
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiffLinearSpace(t *testing.T) {
+	cases := []struct{ a, b string }{
+		{"abc", "abd"},
+		{"abcdef", "dacfea"},
+		{"acbdeacbed", "acebdabbabed"},
+		{"abcbda", "bdcaba"},
+		{"bokko", "bokkko"},
+		{"", ""},
+		{"a", ""},
+		{"", "b"},
+		{"久保竜彦", "久保達彦"},
+		{strings.Repeat("abcbda", 30), strings.Repeat("bdcaba", 25)},
+		{strings.Repeat("acbdeacbed", 20), strings.Repeat("acebdabbabed", 20)},
+		{strings.Repeat("a", 100) + "x" + strings.Repeat("b", 100), strings.Repeat("a", 100) + "y" + strings.Repeat("b", 100)},
+	}
+	for _, c := range cases {
+		expected := New(c.a, c.b)
+		expected.Compose()
+		diff := New(c.a, c.b)
+		diff.LinearSpace()
+		diff.Compose()
+		assert(t, diff.Editdistance() == expected.Editdistance())
+		assert(t, diff.LcsString() == expected.LcsString())
+		assert(t, diff.SprintSes() == expected.SprintSes())
+	}
+}
@@ -0,0 +1,156 @@
+package gonp
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+type stringDiff = DiffOf[string]
+
+// LineDiff is context for calculating difference between a and b line by line
+type LineDiff struct {
+	*stringDiff
+	collapse int
+}
+
+func equalString(x, y string) bool {
+	return x == y
+}
+
+// NewLines is initializer of LineDiff.
+// Each line keeps its trailing "\n", so concatenating lines reconstructs input faithfully.
+// The final line of an input not ending with "\n" has no trailing "\n"
+func NewLines(a, b string) *LineDiff {
+	return &LineDiff{stringDiff: NewTokens(a, b, LineTokenizer), collapse: -1}
+}
+
+// NormalizeLineEndings enables to compare lines ending with "\r\n" as if they end with "\n".
+// SES still carries the original lines, and common lines are taken from a.
+// A final line without any line ending is not equal to the same line with a line ending
+func (diff *LineDiff) NormalizeLineEndings() {
+	diff.eq = func(x, y string) bool {
+		return normalizeLineEnding(x) == normalizeLineEnding(y)
+	}
+	diff.clearHash()
+}
+
+func normalizeLineEnding(line string) string {
+	if strings.HasSuffix(line, "\r\n") {
+		return line[:len(line)-2] + "\n"
+	}
+	return line
+}
+
+// NewStringSlice is initializer of DiffOf comparing each string of a and b as an element
+func NewStringSlice(a, b []string) *DiffOf[string] {
+	return NewSlice(a, b, equalString)
+}
+
+// LineNumbers returns the one-based numbers of the line of each element of SES in a and b.
+// AIndex and BIndex of SES are zero-based indices of lines,
+// so the number is 0 when the line is not in the input like a for additions
+func (diff *LineDiff) LineNumbers() []IndexPair {
+	ses := diff.Ses()
+	numbers := make([]IndexPair, len(ses))
+	for i, e := range ses {
+		numbers[i] = IndexPair{A: e.AIndex + 1, B: e.BIndex + 1}
+	}
+	return numbers
+}
+
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// PrintSes prints shortest edit script between a and b
+func (diff *LineDiff) PrintSes() {
+	diff.FprintSes(os.Stdout)
+}
+
+// SprintSes returns string about shortest edit script between a and b
+func (diff *LineDiff) SprintSes() string {
+	var buf bytes.Buffer
+	diff.FprintSes(&buf)
+	return buf.String()
+}
+
+// FprintSes emit about shortest edit script between a and b to w
+func (diff *LineDiff) FprintSes(w io.Writer) error {
+	return diff.eachCollapsed(func(e SesElemOf[string]) error {
+		var err error
+		switch e.T {
+		case SesDelete:
+			_, err = fmt.Fprintf(w, "- %s", terminateLine(e.V))
+		case SesAdd:
+			_, err = fmt.Fprintf(w, "+ %s", terminateLine(e.V))
+		case SesCommon:
+			_, err = fmt.Fprintf(w, "  %s", terminateLine(e.V))
+		}
+		return err
+	}, func(n int) error {
+		_, err := fmt.Fprintf(w, collapsedFormat, n)
+		return err
+	})
+}
+
+// collapsedFormat is the format of a line replacing unchanged lines collapsed by CollapseUnchanged
+const collapsedFormat = "@@ %d unchanged lines @@\n"
+
+// CollapseUnchanged makes PrintSes, SprintSes, FprintSes and FprintSesColor replace
+// a run of more than one common line with a line like "@@ 240 unchanged lines @@",
+// except for context common lines surrounding each change.
+// It is disabled by a negative context, which is the default
+func (diff *LineDiff) CollapseUnchanged(context int) {
+	diff.collapse = context
+}
+
+// eachCollapsed calls emit for each element of SES shown by CollapseUnchanged
+// and summary for each run of n collapsed common lines until either returns an error
+func (diff *LineDiff) eachCollapsed(emit func(SesElemOf[string]) error, summary func(n int) error) error {
+	ses := diff.Ses()
+	shown := make([]bool, len(ses))
+	for i, e := range ses {
+		if diff.collapse < 0 || e.T != SesCommon {
+			shown[i] = true
+			for j := max(0, i-diff.collapse); j < min(len(ses), i+diff.collapse+1); j++ {
+				shown[j] = true
+			}
+		}
+	}
+	for i := 0; i < len(ses); {
+		j := i
+		for j < len(ses) && !shown[j] {
+			j++
+		}
+		if j-i > 1 {
+			if err := summary(j - i); err != nil {
+				return err
+			}
+			i = j
+			continue
+		}
+		if err := emit(ses[i]); err != nil {
+			return err
+		}
+		i++
+	}
+	return nil
+}
+
+func trimLine(line string) string {
+	return strings.TrimSuffix(line, "\n")
+}
+
+func terminateLine(line string) string {
+	if strings.HasSuffix(line, "\n") {
+		return line
+	}
+	return line + "\n"
+}
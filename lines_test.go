@@ -0,0 +1,128 @@
+package gonp
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiffLines(t *testing.T) {
+	diff := NewLines("a\nb\nc\n", "a\nd\nc\n")
+	diff.Compose()
+	sesActual := diff.Ses()
+	sesExpected := []SesElemOf[string]{
+		{V: "a\n", T: SesCommon},
+		{V: "b\n", T: SesDelete},
+		{V: "d\n", T: SesAdd},
+		{V: "c\n", T: SesCommon},
+	}
+	assert(t, diff.Editdistance() == 2)
+	assert(t, equalsSesElemOfArray(sesActual, sesExpected))
+}
+
+func TestDiffLinesLineNumbers(t *testing.T) {
+	// a is longer than b so that a and b are swapped internally
+	diff := NewLines("a\nb\nc\nd\n", "a\nx\nd\n")
+	numbers := diff.LineNumbers()
+	assert(t, reflect.DeepEqual(numbers, []IndexPair{{1, 1}, {2, 0}, {3, 0}, {0, 2}, {4, 3}}))
+	assert(t, len(numbers) == len(diff.Ses()))
+}
+
+func TestDiffLinesTrailingNewline(t *testing.T) {
+	diff := NewLines("a\nb", "a\nb\n")
+	diff.Compose()
+	sesActual := diff.Ses()
+	sesExpected := []SesElemOf[string]{
+		{V: "a\n", T: SesCommon},
+		{V: "b", T: SesDelete},
+		{V: "b\n", T: SesAdd},
+	}
+	assert(t, diff.Editdistance() == 2)
+	assert(t, equalsSesElemOfArray(sesActual, sesExpected))
+}
+
+func TestDiffLinesEmptyFinalLine(t *testing.T) {
+	diff := NewLines("a\n", "a\n\n")
+	diff.Compose()
+	sesActual := diff.Ses()
+	sesExpected := []SesElemOf[string]{
+		{V: "a\n", T: SesCommon},
+		{V: "\n", T: SesAdd},
+	}
+	assert(t, diff.Editdistance() == 1)
+	assert(t, equalsSesElemOfArray(sesActual, sesExpected))
+}
+
+func TestDiffLinesSprintSes(t *testing.T) {
+	diff := NewLines("a\nb", "a\nc")
+	diff.Compose()
+	ses := diff.SprintSes()
+	assert(t, ses == "  a\n- b\n+ c\n")
+}
+
+func TestDiffStringSlice(t *testing.T) {
+	diff := NewStringSlice([]string{"foo", "bar", "baz"}, []string{"foo", "baz", "qux"})
+	diff.Compose()
+	sesExpected := []SesElemOf[string]{
+		{V: "foo", T: SesCommon},
+		{V: "bar", T: SesDelete},
+		{V: "baz", T: SesCommon},
+		{V: "qux", T: SesAdd},
+	}
+	assert(t, diff.Editdistance() == 2)
+	assert(t, equalsSesElemOfArray(diff.Ses(), sesExpected))
+}
+
+func TestDiffLinesNormalizeLineEndings(t *testing.T) {
+	diff := NewLines("a\r\nb\r\nc", "a\nx\nc\n")
+	diff.NormalizeLineEndings()
+	diff.Compose()
+	sesExpected := []SesElemOf[string]{
+		{V: "a\r\n", T: SesCommon},
+		{V: "b\r\n", T: SesDelete},
+		{V: "c", T: SesDelete},
+		{V: "x\n", T: SesAdd},
+		{V: "c\n", T: SesAdd},
+	}
+	assert(t, diff.Editdistance() == 4)
+	assert(t, equalsSesElemOfArray(diff.Ses(), sesExpected))
+}
+
+func TestLineDiffCollapseUnchanged(t *testing.T) {
+	a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n"
+	b := "1\n2\n3\n4\nfive\n6\n7\n8\n9\n10\n"
+	diff := NewLines(a, b)
+	diff.CollapseUnchanged(1)
+	assert(t, diff.SprintSes() == `@@ 3 unchanged lines @@
+  4
+- 5
++ five
+  6
+@@ 4 unchanged lines @@
+`)
+
+	// a single common line is never collapsed
+	diff.CollapseUnchanged(3)
+	assert(t, diff.SprintSes() == `  1
+  2
+  3
+  4
+- 5
++ five
+  6
+  7
+  8
+@@ 2 unchanged lines @@
+`)
+
+	diff.CollapseUnchanged(-1)
+	assert(t, strings.Count(diff.SprintSes(), "\n") == 11)
+
+	diff = NewLines(a, a)
+	diff.CollapseUnchanged(0)
+	assert(t, diff.SprintSes() == "@@ 10 unchanged lines @@\n")
+	var buf bytes.Buffer
+	diff.FprintSesColor(&buf)
+	assert(t, buf.String() == "@@ 10 unchanged lines @@\n")
+}
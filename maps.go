@@ -0,0 +1,52 @@
+package gonp
+
+import (
+	"sort"
+)
+
+// ValueChange is a change of the value of a key between maps
+type ValueChange struct {
+	Old, New string
+}
+
+// MapDiff is difference between maps a and b by keys and values
+type MapDiff struct {
+	// Added has keys only in b with their values
+	Added map[string]string
+	// Removed has keys only in a with their values
+	Removed map[string]string
+	// Changed has keys in both a and b whose values are different
+	Changed map[string]ValueChange
+}
+
+// DiffMaps returns difference between a and b.
+// Sorted keys of a and b are compared by diff, and values of common keys are compared with each other
+func DiffMaps(a, b map[string]string) *MapDiff {
+	result := &MapDiff{
+		Added:   make(map[string]string),
+		Removed: make(map[string]string),
+		Changed: make(map[string]ValueChange),
+	}
+	for _, e := range NewStringSlice(sortedKeys(a), sortedKeys(b)).Ses() {
+		switch e.T {
+		case SesDelete:
+			result.Removed[e.V] = a[e.V]
+		case SesAdd:
+			result.Added[e.V] = b[e.V]
+		case SesCommon:
+			if a[e.V] != b[e.V] {
+				result.Changed[e.V] = ValueChange{Old: a[e.V], New: b[e.V]}
+			}
+		}
+	}
+	return result
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
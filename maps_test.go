@@ -0,0 +1,21 @@
+package gonp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffMaps(t *testing.T) {
+	a := map[string]string{"host": "localhost", "port": "80", "debug": "true", "user": "root"}
+	b := map[string]string{"host": "example.com", "port": "80", "user": "root", "timeout": "30s"}
+	result := DiffMaps(a, b)
+	assert(t, reflect.DeepEqual(result.Added, map[string]string{"timeout": "30s"}))
+	assert(t, reflect.DeepEqual(result.Removed, map[string]string{"debug": "true"}))
+	assert(t, reflect.DeepEqual(result.Changed, map[string]ValueChange{"host": {Old: "localhost", New: "example.com"}}))
+
+	result = DiffMaps(nil, map[string]string{"k": "v"})
+	assert(t, len(result.Added) == 1 && len(result.Removed) == 0 && len(result.Changed) == 0)
+
+	result = DiffMaps(a, a)
+	assert(t, len(result.Added) == 0 && len(result.Removed) == 0 && len(result.Changed) == 0)
+}
@@ -0,0 +1,33 @@
+package gonp
+
+import (
+	"strings"
+)
+
+// Markdown returns unified format diff between a and b with 3 lines of context
+// in a fenced code block of Markdown highlighted as diff.
+// Since backticks cannot be escaped in a code block, the fence is longer than
+// any run of backticks in the diff so that lines like " ```" do not close the block.
+// It returns "" when a and b are equal
+func (diff *LineDiff) Markdown() string {
+	unified := diff.UnifiedDiff("a", "b", 3)
+	if unified == "" {
+		return ""
+	}
+	fence := strings.Repeat("`", max(3, longestRun(unified, '`')+1))
+	return fence + "diff\n" + unified + fence + "\n"
+}
+
+// longestRun returns the length of the longest run of c in s
+func longestRun(s string, c byte) int {
+	longest, run := 0, 0
+	for i := 0; i < len(s); i++ {
+		if s[i] != c {
+			run = 0
+			continue
+		}
+		run++
+		longest = max(longest, run)
+	}
+	return longest
+}
@@ -0,0 +1,18 @@
+package gonp
+
+import (
+	"testing"
+)
+
+func TestMarkdown(t *testing.T) {
+	diff := NewLines("a\nb\n", "a\nc\n")
+	assert(t, diff.Markdown() == "```diff\n--- a\n+++ b\n@@ -1,2 +1,2 @@\n a\n-b\n+c\n```\n")
+
+	// the fence is longer than backticks in content
+	diff = NewLines("```go\nx\n```\n", "```go\ny\n```\n")
+	assert(t, diff.Markdown() == "````diff\n--- a\n+++ b\n@@ -1,3 +1,3 @@\n ```go\n-x\n+y\n ```\n````\n")
+	diff = NewLines("`````\n", "")
+	assert(t, diff.Markdown() == "``````diff\n--- a\n+++ b\n@@ -1 +0,0 @@\n-`````\n``````\n")
+
+	assert(t, NewLines("a\n", "a\n").Markdown() == "")
+}
@@ -0,0 +1,114 @@
+package gonp
+
+import (
+	"strings"
+)
+
+// Conflict is a region changed differently in mine and theirs on three-way merge.
+// Line is the line number of the conflict marker in merged text
+type Conflict struct {
+	Line   int
+	Base   string
+	Mine   string
+	Theirs string
+}
+
+// Merge performs three-way merge of mine and theirs, which are derived from base, line by line.
+// Changes which do not overlap are merged cleanly. Overlapping changes are emitted between
+// conflict markers and reported as conflicts
+func Merge(base, mine, theirs string) (string, []Conflict, error) {
+	baseLines := splitLines(base)
+	mineLines := splitLines(mine)
+	theirsLines := splitLines(theirs)
+	matchMine := matchLines(baseLines, mineLines)
+	matchTheirs := matchLines(baseLines, theirsLines)
+
+	var buf strings.Builder
+	conflicts := make([]Conflict, 0)
+	line := 1
+	emit := func(lines []string) {
+		for _, l := range lines {
+			buf.WriteString(l)
+			line++
+		}
+	}
+
+	i, im, it := 0, 0, 0
+	for i < len(baseLines) || im < len(mineLines) || it < len(theirsLines) {
+		if i < len(baseLines) && matchMine[i] == im && matchTheirs[i] == it {
+			emit(baseLines[i : i+1])
+			i++
+			im++
+			it++
+			continue
+		}
+
+		j := i
+		for j < len(baseLines) && (matchMine[j] < 0 || matchTheirs[j] < 0) {
+			j++
+		}
+		jm, jt := len(mineLines), len(theirsLines)
+		if j < len(baseLines) {
+			jm, jt = matchMine[j], matchTheirs[j]
+		}
+		b, m, t := baseLines[i:j], mineLines[im:jm], theirsLines[it:jt]
+		switch {
+		case equalLines(m, b):
+			emit(t)
+		case equalLines(t, b), equalLines(m, t):
+			emit(m)
+		default:
+			conflicts = append(conflicts, Conflict{
+				Line:   line,
+				Base:   strings.Join(b, ""),
+				Mine:   strings.Join(m, ""),
+				Theirs: strings.Join(t, ""),
+			})
+			emit([]string{"<<<<<<< mine\n"})
+			emit(terminateLines(m))
+			emit([]string{"=======\n"})
+			emit(terminateLines(t))
+			emit([]string{">>>>>>> theirs\n"})
+		}
+		i, im, it = j, jm, jt
+	}
+	return buf.String(), conflicts, nil
+}
+
+// matchLines returns positions in b of lines in a matched by LCS, or -1 for unmatched lines
+func matchLines(a, b []string) []int {
+	match := make([]int, len(a))
+	for i := range match {
+		match[i] = -1
+	}
+	diff := NewSlice(a, b, equalString)
+	for _, e := range diff.Ses() {
+		if e.T == SesCommon {
+			match[e.AIndex] = e.BIndex
+		}
+	}
+	return match
+}
+
+func equalLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// terminateLines ensures the last line ends with "\n" so that a conflict marker starts a new line
+func terminateLines(lines []string) []string {
+	if len(lines) == 0 || strings.HasSuffix(lines[len(lines)-1], "\n") {
+		return lines
+	}
+	terminated := make([]string, len(lines))
+	copy(terminated, lines)
+	terminated[len(lines)-1] += "\n"
+	return terminated
+}
@@ -0,0 +1,40 @@
+package gonp
+
+import (
+	"testing"
+)
+
+func TestMergeClean(t *testing.T) {
+	base := "a\nb\nc\nd\ne\n"
+	mine := "A\nb\nc\nd\ne\n"
+	theirs := "a\nb\nc\nd\nE\nf\n"
+	merged, conflicts, err := Merge(base, mine, theirs)
+	assert(t, err == nil)
+	assert(t, len(conflicts) == 0)
+	assert(t, merged == "A\nb\nc\nd\nE\nf\n")
+}
+
+func TestMergeSameChange(t *testing.T) {
+	merged, conflicts, err := Merge("a\nb\nc\n", "a\nx\nc\n", "a\nx\nc\n")
+	assert(t, err == nil)
+	assert(t, len(conflicts) == 0)
+	assert(t, merged == "a\nx\nc\n")
+}
+
+func TestMergeConflict(t *testing.T) {
+	base := "a\nb\nc\n"
+	mine := "a\nmine\nc\n"
+	theirs := "a\ntheirs\nc\n"
+	merged, conflicts, err := Merge(base, mine, theirs)
+	assert(t, err == nil)
+	assert(t, merged == "a\n<<<<<<< mine\nmine\n=======\ntheirs\n>>>>>>> theirs\nc\n")
+	assert(t, len(conflicts) == 1)
+	assert(t, conflicts[0] == Conflict{Line: 2, Base: "b\n", Mine: "mine\n", Theirs: "theirs\n"})
+}
+
+func TestMergeConflictWithoutTrailingNewline(t *testing.T) {
+	merged, conflicts, err := Merge("a\nb", "a\nc", "a\nd")
+	assert(t, err == nil)
+	assert(t, len(conflicts) == 1)
+	assert(t, merged == "a\n<<<<<<< mine\nc\n=======\nd\n>>>>>>> theirs\n")
+}
@@ -0,0 +1,55 @@
+package gonp
+
+// DetectMoves returns SES in which each deleted run equal to an added run elsewhere
+// is re-tagged as SesMove with the added run. A moved element appears at both positions
+// in a and b, and both AIndex and BIndex of it are set to link the two positions.
+// The result of Ses is not changed
+func (diff *DiffOf[T]) DetectMoves() []SesElemOf[T] {
+	ses := make([]SesElemOf[T], len(diff.Ses()))
+	copy(ses, diff.Ses())
+
+	type run struct {
+		start, length int
+		moved         bool
+	}
+	dels, adds := make([]run, 0), make([]run, 0)
+	for i := 0; i < len(ses); {
+		j := i
+		for j < len(ses) && ses[j].T == ses[i].T {
+			j++
+		}
+		switch ses[i].T {
+		case SesDelete:
+			dels = append(dels, run{start: i, length: j - i})
+		case SesAdd:
+			adds = append(adds, run{start: i, length: j - i})
+		}
+		i = j
+	}
+
+	for _, d := range dels {
+		for k := range adds {
+			a := &adds[k]
+			if a.moved || a.length != d.length || !diff.equalRun(ses, d.start, a.start, d.length) {
+				continue
+			}
+			a.moved = true
+			for i := 0; i < d.length; i++ {
+				from, to := &ses[d.start+i], &ses[a.start+i]
+				from.T, to.T = SesMove, SesMove
+				from.BIndex, to.AIndex = to.BIndex, from.AIndex
+			}
+			break
+		}
+	}
+	return ses
+}
+
+func (diff *DiffOf[T]) equalRun(ses []SesElemOf[T], i, j, length int) bool {
+	for n := 0; n < length; n++ {
+		if !diff.eq(ses[i+n].V, ses[j+n].V) {
+			return false
+		}
+	}
+	return true
+}
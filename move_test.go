@@ -0,0 +1,32 @@
+package gonp
+
+import (
+	"testing"
+)
+
+func TestDetectMoves(t *testing.T) {
+	diff := NewLines("a\nb\nc\nd\n", "c\nd\na\nb\n")
+	diff.Compose()
+	ses := diff.DetectMoves()
+	sesExpected := []SesElemOf[string]{
+		{V: "a\n", T: SesMove, AIndex: 0, BIndex: 2},
+		{V: "b\n", T: SesMove, AIndex: 1, BIndex: 3},
+		{V: "c\n", T: SesCommon, AIndex: 2, BIndex: 0},
+		{V: "d\n", T: SesCommon, AIndex: 3, BIndex: 1},
+		{V: "a\n", T: SesMove, AIndex: 0, BIndex: 2},
+		{V: "b\n", T: SesMove, AIndex: 1, BIndex: 3},
+	}
+	assert(t, len(ses) == len(sesExpected))
+	for i := range sesExpected {
+		assert(t, ses[i] == sesExpected[i])
+	}
+	for _, e := range diff.Ses() {
+		assert(t, e.T != SesMove)
+	}
+}
+
+func TestDetectMovesNoMove(t *testing.T) {
+	diff := New("abc", "abd")
+	ses := diff.DetectMoves()
+	assert(t, equalsSesElemOfArray(ses, diff.Ses()))
+}
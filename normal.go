@@ -0,0 +1,54 @@
+package gonp
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NormalFormat returns GNU diff's normal format diff between a and b like "3,4c3,4", "5a6" and "2d1"
+// followed by deleted lines with "< " and added lines with "> "
+func (diff *LineDiff) NormalFormat() string {
+	var buf strings.Builder
+	for _, h := range diff.lineHunks(0) {
+		dels, adds := make([]string, 0), make([]string, 0)
+		for _, e := range h.ses {
+			switch e.T {
+			case SesDelete:
+				dels = append(dels, e.V)
+			case SesAdd:
+				adds = append(adds, e.V)
+			}
+		}
+		switch {
+		case len(adds) == 0:
+			fmt.Fprintf(&buf, "%sd%d\n", normalRange(h.aStart, h.aLen), h.bStart-1)
+		case len(dels) == 0:
+			fmt.Fprintf(&buf, "%da%s\n", h.aStart-1, normalRange(h.bStart, h.bLen))
+		default:
+			fmt.Fprintf(&buf, "%sc%s\n", normalRange(h.aStart, h.aLen), normalRange(h.bStart, h.bLen))
+		}
+		writeNormalLines(&buf, "< ", dels)
+		if len(dels) > 0 && len(adds) > 0 {
+			buf.WriteString("---\n")
+		}
+		writeNormalLines(&buf, "> ", adds)
+	}
+	return buf.String()
+}
+
+func normalRange(start, length int) string {
+	if length == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, start+length-1)
+}
+
+func writeNormalLines(buf *strings.Builder, prefix string, lines []string) {
+	for _, line := range lines {
+		buf.WriteString(prefix)
+		buf.WriteString(terminateLine(line))
+		if !strings.HasSuffix(line, "\n") {
+			buf.WriteString(noNewlineMarker)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package gonp
+
+import (
+	"testing"
+)
+
+// expected outputs are generated by diff of GNU diffutils 3.8
+func TestNormalFormat(t *testing.T) {
+	tests := []struct {
+		a, b, expected string
+	}{
+		{"a\nb\nc\nd\ne\n", "a\nB\nC\nd\ne\nf\n", "2,3c2,3\n< b\n< c\n---\n> B\n> C\n5a6\n> f\n"},
+		{"x\na\nb\nc", "a\nc\nz", "1d0\n< x\n3,4c2,3\n< b\n< c\n\\ No newline at end of file\n---\n> c\n> z\n\\ No newline at end of file\n"},
+		{"a\nb\nc\n", "a\nx\ny\nc\n", "2c2,3\n< b\n---\n> x\n> y\n"},
+		{"x\ny\nz\n", "p\n", "1,3c1\n< x\n< y\n< z\n---\n> p\n"},
+		{"a\n", "a\n", ""},
+	}
+	for _, tt := range tests {
+		diff := NewLines(tt.a, tt.b)
+		assert(t, diff.NormalFormat() == tt.expected)
+	}
+}
@@ -0,0 +1,29 @@
+//go:build gonpnorm
+
+package gonp
+
+import (
+	"golang.org/x/text/unicode/norm"
+)
+
+// NewNormalized is initializer of DiffOf comparing a and b after normalizing them in form.
+// a and b are split into segments of characters so that each segment is normalized independently,
+// and SES carries the original segments. This is available with the build tag gonpnorm
+func NewNormalized(a, b string, form norm.Form) *DiffOf[string] {
+	return NewSlice(splitNormSegments(a, form), splitNormSegments(b, form), func(x, y string) bool {
+		return form.String(x) == form.String(y)
+	})
+}
+
+func splitNormSegments(s string, form norm.Form) []string {
+	segments := make([]string, 0, len(s))
+	for s != "" {
+		i := form.NextBoundaryInString(s, true)
+		if i <= 0 {
+			i = len(s)
+		}
+		segments = append(segments, s[:i])
+		s = s[i:]
+	}
+	return segments
+}
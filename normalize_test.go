@@ -0,0 +1,25 @@
+//go:build gonpnorm
+
+package gonp
+
+import (
+	"testing"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+func TestDiffNormalized(t *testing.T) {
+	nfc, nfd := "caf\u00e9!", "cafe\u0301?"
+	diff := NewNormalized(nfc, nfd, norm.NFC)
+	diff.Compose()
+	sesExpected := []SesElemOf[string]{
+		{V: "c", T: SesCommon},
+		{V: "a", T: SesCommon},
+		{V: "f", T: SesCommon},
+		{V: "\u00e9", T: SesCommon},
+		{V: "!", T: SesDelete},
+		{V: "?", T: SesAdd},
+	}
+	assert(t, diff.Editdistance() == 2)
+	assert(t, equalsSesElemOfArray(diff.Ses(), sesExpected))
+}
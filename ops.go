@@ -0,0 +1,152 @@
+package gonp
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// EditOp is a run of consecutive elements of SES of the same manipulaton type.
+// Text is the concatenation of the elements. For SesReplace, Text is the added text
+// and Old is the deleted text, while Old is empty for the other types
+type EditOp struct {
+	Type SesType
+	Text string
+	Old  string
+}
+
+// Ops returns SES between a and b as runs of characters
+func (diff *Diff) Ops() []EditOp {
+	return sesOps(diff.Ses(), func(buf *strings.Builder, r rune) {
+		buf.WriteRune(r)
+	})
+}
+
+// Ops returns SES between a and b as runs of lines
+func (diff *LineDiff) Ops() []EditOp {
+	return sesOps(diff.Ses(), func(buf *strings.Builder, line string) {
+		buf.WriteString(line)
+	})
+}
+
+// CoalesceSubstitutions returns SES between a and b as runs of characters like Ops,
+// where a run of deletions adjacent to a run of additions is replaced with a SesReplace run
+func (diff *Diff) CoalesceSubstitutions() []EditOp {
+	return coalesceSubstitutions(diff.Ops())
+}
+
+// CoalesceSubstitutions returns SES between a and b as runs of lines like Ops,
+// where a run of deletions adjacent to a run of additions is replaced with a SesReplace run
+func (diff *LineDiff) CoalesceSubstitutions() []EditOp {
+	return coalesceSubstitutions(diff.Ops())
+}
+
+// coalesceSubstitutions merges each pair of adjacent deletion and addition in ops in either order
+func coalesceSubstitutions(ops []EditOp) []EditOp {
+	coalesced := make([]EditOp, 0, len(ops))
+	for i := 0; i < len(ops); i++ {
+		if i+1 < len(ops) {
+			switch {
+			case ops[i].Type == SesDelete && ops[i+1].Type == SesAdd:
+				coalesced = append(coalesced, EditOp{Type: SesReplace, Text: ops[i+1].Text, Old: ops[i].Text})
+				i++
+				continue
+			case ops[i].Type == SesAdd && ops[i+1].Type == SesDelete:
+				coalesced = append(coalesced, EditOp{Type: SesReplace, Text: ops[i].Text, Old: ops[i+1].Text})
+				i++
+				continue
+			}
+		}
+		coalesced = append(coalesced, ops[i])
+	}
+	return coalesced
+}
+
+// ApplyOps reconstructs b from a and ops returned by Ops or CoalesceSubstitutions
+func ApplyOps(a string, ops []EditOp) (string, error) {
+	var buf strings.Builder
+	rest := a
+	pos := 0
+	consume := func(i int, text string) error {
+		if !strings.HasPrefix(rest, text) {
+			return fmt.Errorf("gonp: op %d %q does not match a at position %d", i, text, pos)
+		}
+		rest = rest[len(text):]
+		pos += utf8.RuneCountInString(text)
+		return nil
+	}
+	for i, op := range ops {
+		var err error
+		switch op.Type {
+		case SesCommon:
+			err = consume(i, op.Text)
+			buf.WriteString(op.Text)
+		case SesDelete:
+			err = consume(i, op.Text)
+		case SesAdd:
+			buf.WriteString(op.Text)
+		case SesReplace:
+			err = consume(i, op.Old)
+			buf.WriteString(op.Text)
+		default:
+			err = fmt.Errorf("gonp: op %d has unsupported type %s", i, op.Type)
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	if rest != "" {
+		return "", fmt.Errorf("gonp: ops end before a at position %d", pos)
+	}
+	return buf.String(), nil
+}
+
+func sesOps[T any](ses []SesElemOf[T], write func(*strings.Builder, T)) []EditOp {
+	ops := make([]EditOp, 0)
+	var buf strings.Builder
+	for i := 0; i < len(ses); {
+		j := i
+		for ; j < len(ses) && ses[j].T == ses[i].T; j++ {
+			write(&buf, ses[j].V)
+		}
+		ops = append(ops, EditOp{Type: ses[i].T, Text: buf.String()})
+		buf.Reset()
+		i = j
+	}
+	return ops
+}
+
+// PositionedOp replaces ALen characters of a from AStart with Insert
+type PositionedOp struct {
+	AStart, ALen int
+	Insert       string
+}
+
+// PositionedOps returns operations transforming a into b. Adjacent deletions and additions
+// are coalesced into an operation. Positions refer to a, so applying operations
+// in reverse order to a copy of a reconstructs b without adjusting positions
+func (diff *Diff) PositionedOps() []PositionedOp {
+	ops := make([]PositionedOp, 0)
+	ses := diff.Ses()
+	pos := 0
+	for i := 0; i < len(ses); {
+		if ses[i].T == SesCommon {
+			pos++
+			i++
+			continue
+		}
+		op := PositionedOp{AStart: pos}
+		var buf strings.Builder
+		for ; i < len(ses) && ses[i].T != SesCommon; i++ {
+			if ses[i].T == SesDelete {
+				op.ALen++
+				pos++
+			} else {
+				buf.WriteRune(ses[i].V)
+			}
+		}
+		op.Insert = buf.String()
+		ops = append(ops, op)
+	}
+	return ops
+}
@@ -0,0 +1,112 @@
+package gonp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOps(t *testing.T) {
+	diff := New("abcdef", "abXYef")
+	assert(t, reflect.DeepEqual(diff.Ops(), []EditOp{
+		{Type: SesCommon, Text: "ab"},
+		{Type: SesDelete, Text: "cd"},
+		{Type: SesAdd, Text: "XY"},
+		{Type: SesCommon, Text: "ef"},
+	}))
+	assert(t, len(New("", "").Ops()) == 0)
+	assert(t, reflect.DeepEqual(New("", "日本").Ops(), []EditOp{{Type: SesAdd, Text: "日本"}}))
+}
+
+func TestLineDiffOps(t *testing.T) {
+	diff := NewLines("a\nb\nc\n", "a\nx\ny\nc\n")
+	assert(t, reflect.DeepEqual(diff.Ops(), []EditOp{
+		{Type: SesCommon, Text: "a\n"},
+		{Type: SesAdd, Text: "x\ny\n"},
+		{Type: SesDelete, Text: "b\n"},
+		{Type: SesCommon, Text: "c\n"},
+	}))
+}
+
+func TestCoalesceSubstitutions(t *testing.T) {
+	diff := New("abcdef", "abXYef!")
+	assert(t, reflect.DeepEqual(diff.CoalesceSubstitutions(), []EditOp{
+		{Type: SesCommon, Text: "ab"},
+		{Type: SesReplace, Text: "XY", Old: "cd"},
+		{Type: SesCommon, Text: "ef"},
+		{Type: SesAdd, Text: "!"},
+	}))
+	assert(t, diff.Editdistance() == 5)
+
+	// additions may precede deletions when a is shorter than b
+	lines := NewLines("a\nb\nc\n", "a\nx\ny\nc\n")
+	assert(t, reflect.DeepEqual(lines.CoalesceSubstitutions(), []EditOp{
+		{Type: SesCommon, Text: "a\n"},
+		{Type: SesReplace, Text: "x\ny\n", Old: "b\n"},
+		{Type: SesCommon, Text: "c\n"},
+	}))
+	assert(t, len(New("", "").CoalesceSubstitutions()) == 0)
+}
+
+func TestApplyOps(t *testing.T) {
+	pairs := [][2]string{
+		{"abcdef", "abXYef!"},
+		{"", "new"},
+		{"old", ""},
+		{"久保竜彦", "久保達彦"},
+		{"acbdeacbed", "acebdabbabed"},
+	}
+	for _, pair := range pairs {
+		diff := New(pair[0], pair[1])
+		for _, ops := range [][]EditOp{diff.Ops(), diff.CoalesceSubstitutions()} {
+			b, err := ApplyOps(pair[0], ops)
+			assert(t, err == nil)
+			assert(t, b == pair[1])
+		}
+	}
+
+	lines := NewLines("a\nb\nc\n", "a\nx\ny\nc\n")
+	b, err := ApplyOps("a\nb\nc\n", lines.CoalesceSubstitutions())
+	assert(t, err == nil && b == "a\nx\ny\nc\n")
+}
+
+func TestApplyOpsMismatch(t *testing.T) {
+	ops := New("久保竜彦", "久保達彦").CoalesceSubstitutions()
+	for _, c := range []struct{ a, msg string }{
+		{"久保竜", "gonp: op 2 \"彦\" does not match a at position 3"},
+		{"久保x彦", "gonp: op 1 \"竜\" does not match a at position 2"},
+		{"久保竜彦!", "gonp: ops end before a at position 4"},
+	} {
+		_, err := ApplyOps(c.a, ops)
+		assert(t, err != nil && err.Error() == c.msg)
+	}
+	_, err := ApplyOps("a", []EditOp{{Type: SesMove, Text: "a"}})
+	assert(t, err != nil && err.Error() == "gonp: op 0 has unsupported type Move")
+}
+
+func TestPositionedOps(t *testing.T) {
+	diff := New("abcdef", "abXYef!")
+	assert(t, reflect.DeepEqual(diff.PositionedOps(), []PositionedOp{
+		{AStart: 2, ALen: 2, Insert: "XY"},
+		{AStart: 6, ALen: 0, Insert: "!"},
+	}))
+	assert(t, len(New("abc", "abc").PositionedOps()) == 0)
+}
+
+func TestPositionedOpsApply(t *testing.T) {
+	pairs := [][2]string{
+		{"abcdef", "abXYef!"},
+		{"", "abc"},
+		{"abc", ""},
+		{"acbdeacbed", "acebdabbabed"},
+		{"久保竜彦", "久保達彦です"},
+	}
+	for _, pair := range pairs {
+		buf := []rune(pair[0])
+		ops := New(pair[0], pair[1]).PositionedOps()
+		for i := len(ops) - 1; i >= 0; i-- {
+			op := ops[i]
+			buf = append(buf[:op.AStart:op.AStart], append([]rune(op.Insert), buf[op.AStart+op.ALen:]...)...)
+		}
+		assert(t, string(buf) == pair[1])
+	}
+}
@@ -0,0 +1,108 @@
+package gonp
+
+// Option is option for configuring Diff in NewWith
+type Option func(*Diff)
+
+// OnlyEd is option to calculate only edit distance like Diff.OnlyEd
+func OnlyEd() Option {
+	return func(diff *Diff) {
+		diff.OnlyEd()
+	}
+}
+
+// IgnoreCase is option to compare characters case-insensitively like Diff.IgnoreCase
+func IgnoreCase() Option {
+	return func(diff *Diff) {
+		diff.IgnoreCase()
+	}
+}
+
+// IgnoreWhitespace is option to treat whitespace characters as equal like Diff.IgnoreWhitespace
+func IgnoreWhitespace() Option {
+	return func(diff *Diff) {
+		diff.IgnoreWhitespace()
+	}
+}
+
+// IgnoreRunes is option to ignore the characters in set when matching like Diff.IgnoreRunes
+func IgnoreRunes(set string) Option {
+	return func(diff *Diff) {
+		diff.IgnoreRunes(set)
+	}
+}
+
+// EqualFunc is option to compare characters by fn like Diff.SetEqual
+func EqualFunc(fn func(x, y rune) bool) Option {
+	return func(diff *Diff) {
+		diff.SetEqual(fn)
+	}
+}
+
+// Limit is option to limit SES to the first n elements which are not SesCommon like Diff.Limit.
+// SES is not limited by default
+func Limit(n int) Option {
+	return func(diff *Diff) {
+		diff.Limit(n)
+	}
+}
+
+// MaxScriptElements is option to limit SES to the first n elements like Diff.MaxScriptElements.
+// SES is not limited by default
+func MaxScriptElements(n int) Option {
+	return func(diff *Diff) {
+		diff.MaxScriptElements(n)
+	}
+}
+
+// MaxEd is option to give up when edit distance exceeds limit like Diff.MaxEd.
+// Edit distance is not limited by default
+func MaxEd(limit int) Option {
+	return func(diff *Diff) {
+		diff.MaxEd(limit)
+	}
+}
+
+// NoSwap is option not to swap a and b internally like Diff.NoSwap
+func NoSwap() Option {
+	return func(diff *Diff) {
+		diff.NoSwap()
+	}
+}
+
+// LinearSpace is option to compose SES in O(M+N) space like Diff.LinearSpace
+func LinearSpace() Option {
+	return func(diff *Diff) {
+		diff.LinearSpace()
+	}
+}
+
+// Fast is option to compose approximately shortest SES quickly like Diff.Fast
+func Fast() Option {
+	return func(diff *Diff) {
+		diff.Fast()
+	}
+}
+
+// PreferDeletesFirst is option to make deletions precede additions like Diff.PreferDeletesFirst
+func PreferDeletesFirst() Option {
+	return func(diff *Diff) {
+		diff.PreferDeletesFirst()
+	}
+}
+
+// AlignSuffix is option to favor matches toward the end like Diff.AlignSuffix
+func AlignSuffix() Option {
+	return func(diff *Diff) {
+		diff.AlignSuffix()
+	}
+}
+
+// NewWith is initializer of Diff configured by opts in order.
+// Without any options, it is the same as New
+func NewWith(a, b string, opts ...Option) *Diff {
+	diff := NewRunes([]rune(a), []rune(b))
+	for _, opt := range opts {
+		opt(diff)
+	}
+	return diff
+}
@@ -0,0 +1,35 @@
+package gonp
+
+import (
+	"testing"
+)
+
+func TestNewWith(t *testing.T) {
+	assert(t, NewWith("abc", "abd").SprintSes() == New("abc", "abd").SprintSes())
+
+	diff := NewWith("Hello World", "hello  world", IgnoreCase(), IgnoreWhitespace())
+	assert(t, diff.Editdistance() == 0)
+
+	diff = NewWith("abc", "xyz", Limit(2))
+	assert(t, len(diff.Ses()) == 2 && diff.Truncated())
+
+	diff = NewWith("abc", "xyz", MaxEd(3))
+	assert(t, diff.Editdistance() == -1)
+
+	diff = NewWith("abc", "abd", OnlyEd())
+	assert(t, diff.Editdistance() == 2 && diff.Ses() == nil)
+
+	diff = NewWith("abcd", "ab", NoSwap())
+	assert(t, !diff.Reversed())
+
+	diff = NewWith("ab", "abab", AlignSuffix())
+	assert(t, diff.SprintSes() == "+ a\n+ b\n  a\n  b\n")
+
+	diff = NewWith("axyc", "abc", PreferDeletesFirst(), LinearSpace())
+	assert(t, diff.SprintSes() == "  a\n- x\n- y\n+ b\n  c\n")
+
+	diff = NewWith("a1", "a2", EqualFunc(func(x, y rune) bool {
+		return x == y || x >= '0' && x <= '9' && y >= '0' && y <= '9'
+	}))
+	assert(t, diff.Editdistance() == 0)
+}
@@ -0,0 +1,36 @@
+package gonp
+
+import (
+	"strings"
+)
+
+// NewParagraphs is initializer of DiffOf comparing a and b paragraph by paragraph.
+// Paragraphs are runs of non-blank lines, and runs of blank lines between them are
+// single tokens, so concatenating tokens reconstructs input.
+// A blank line consists of whitespace only
+func NewParagraphs(a, b string) *DiffOf[string] {
+	return NewTokens(a, b, ParagraphTokenizer)
+}
+
+func isBlankLine(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
+
+func splitParagraphs(s string) []string {
+	paragraphs := make([]string, 0)
+	start, blank := 0, false
+	pos := 0
+	for _, line := range splitLines(s) {
+		b := isBlankLine(line)
+		if pos > start && b != blank {
+			paragraphs = append(paragraphs, s[start:pos])
+			start = pos
+		}
+		blank = b
+		pos += len(line)
+	}
+	if start < len(s) {
+		paragraphs = append(paragraphs, s[start:])
+	}
+	return paragraphs
+}
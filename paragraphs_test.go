@@ -0,0 +1,42 @@
+package gonp
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSplitParagraphs(t *testing.T) {
+	tests := []struct {
+		s          string
+		paragraphs []string
+	}{
+		{"", []string{}},
+		{"one\n", []string{"one\n"}},
+		{"one\ntwo\n\nthree", []string{"one\ntwo\n", "\n", "three"}},
+		{"\n \none\n\n\n\ttwo\n\n", []string{"\n \n", "one\n", "\n\n", "\ttwo\n", "\n"}},
+	}
+	for _, tt := range tests {
+		paragraphs := splitParagraphs(tt.s)
+		assert(t, len(paragraphs) == len(tt.paragraphs))
+		for i := range paragraphs {
+			assert(t, paragraphs[i] == tt.paragraphs[i])
+		}
+		assert(t, strings.Join(paragraphs, "") == tt.s)
+	}
+}
+
+func TestNewParagraphs(t *testing.T) {
+	a := "# Title\n\nFirst paragraph\nwith two lines.\n\nSecond paragraph.\n"
+	b := "# Title\n\nFirst paragraph\nwith 2 lines.\n\nSecond paragraph.\n\nThird.\n"
+	diff := NewParagraphs(a, b)
+	assert(t, equalsSesElemOfArray(diff.Ses(), []SesElemOf[string]{
+		{V: "# Title\n", T: SesCommon},
+		{V: "\n", T: SesCommon},
+		{V: "First paragraph\nwith 2 lines.\n", T: SesAdd},
+		{V: "First paragraph\nwith two lines.\n", T: SesDelete},
+		{V: "\n", T: SesCommon},
+		{V: "Second paragraph.\n", T: SesCommon},
+		{V: "\n", T: SesAdd},
+		{V: "Third.\n", T: SesAdd},
+	}))
+}
@@ -0,0 +1,71 @@
+package gonp
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"unicode/utf8"
+)
+
+var (
+	// ErrInputTooLarge is returned when an input exceeds the size given by MaxBytes
+	ErrInputTooLarge = errors.New("gonp: input exceeds MaxBytes")
+	// ErrInvalidUTF8 is returned when an input is not valid UTF-8
+	ErrInvalidUTF8 = errors.New("gonp: input is not valid UTF-8")
+	// ErrEmptyInput is returned when an input which must not be empty is empty, like a binary patch.
+	// Empty a and b to diff are valid and never result in ErrEmptyInput
+	ErrEmptyInput = errors.New("gonp: input is empty")
+)
+
+type readConfig struct {
+	maxBytes int64
+}
+
+// ReadOption is option for reading inputs in NewReaders
+type ReadOption func(*readConfig)
+
+// MaxBytes limits the size of each input read in NewReaders to n bytes
+func MaxBytes(n int64) ReadOption {
+	return func(c *readConfig) {
+		c.maxBytes = n
+	}
+}
+
+// NewReaders is initializer of Diff which reads a and b fully.
+// Inputs are decoded as UTF-8 and invalid input results in ErrInvalidUTF8
+func NewReaders(a, b io.Reader, opts ...ReadOption) (*Diff, error) {
+	c := readConfig{maxBytes: -1}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	sa, err := readString(a, c)
+	if err != nil {
+		return nil, err
+	}
+	sb, err := readString(b, c)
+	if err != nil {
+		return nil, err
+	}
+	return New(sa, sb), nil
+}
+
+func readString(r io.Reader, c readConfig) (string, error) {
+	if c.maxBytes >= 0 {
+		r = io.LimitReader(r, c.maxBytes+1)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	if c.maxBytes >= 0 && int64(len(data)) > c.maxBytes {
+		return "", ErrInputTooLarge
+	}
+	for i := 0; i < len(data); {
+		ch, size := utf8.DecodeRune(data[i:])
+		if ch == utf8.RuneError && size == 1 {
+			return "", fmt.Errorf("%w at byte %d", ErrInvalidUTF8, i)
+		}
+		i += size
+	}
+	return string(data), nil
+}
@@ -0,0 +1,26 @@
+package gonp
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNewReaders(t *testing.T) {
+	diff, err := NewReaders(strings.NewReader("abc"), strings.NewReader("abd"))
+	assert(t, err == nil)
+	assert(t, diff.Editdistance() == 2)
+	assert(t, diff.LcsString() == "ab")
+}
+
+func TestNewReadersMaxBytes(t *testing.T) {
+	_, err := NewReaders(strings.NewReader("abc"), strings.NewReader("abcd"), MaxBytes(3))
+	assert(t, err == ErrInputTooLarge)
+	_, err = NewReaders(strings.NewReader("abc"), strings.NewReader("abd"), MaxBytes(3))
+	assert(t, err == nil)
+}
+
+func TestNewReadersInvalidUTF8(t *testing.T) {
+	_, err := NewReaders(strings.NewReader("abc"), strings.NewReader("ab\xff"))
+	assert(t, errors.Is(err, ErrInvalidUTF8))
+}
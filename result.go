@@ -0,0 +1,54 @@
+package gonp
+
+import (
+	"runtime"
+	"sync"
+)
+
+// Result is the outcome of diff between a and b.
+// It does not share memory with any Diff, so it is safe to read from multiple goroutines
+type Result struct {
+	Ed  int
+	Lcs []rune
+	Ses []SesElem
+}
+
+// Compute calculates difference between a and b and returns it as Result.
+// Compute is safe for concurrent use since it retains no state between calls
+func Compute(a, b string) (*Result, error) {
+	diff := New(a, b)
+	diff.Compose()
+	return &Result{
+		Ed:  diff.Editdistance(),
+		Lcs: diff.Lcs(),
+		Ses: diff.Ses(),
+	}, nil
+}
+
+// ComputeAll calculates difference between each pair of a and b with workers goroutines
+// and returns results in the same order as pairs.
+// GOMAXPROCS goroutines are used when workers is not positive
+func ComputeAll(pairs [][2]string, workers int) []*Result {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	workers = min(workers, len(pairs))
+	results := make([]*Result, len(pairs))
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				results[i], _ = Compute(pairs[i][0], pairs[i][1])
+			}
+		}()
+	}
+	for i := range pairs {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+	return results
+}
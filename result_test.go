@@ -0,0 +1,57 @@
+package gonp
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestCompute(t *testing.T) {
+	r, err := Compute("abc", "abd")
+	assert(t, err == nil)
+	assert(t, r.Ed == 2)
+	assert(t, string(r.Lcs) == "ab")
+	assert(t, equalsSesElemOfArray(r.Ses, []SesElem{
+		{V: 'a', T: SesCommon},
+		{V: 'b', T: SesCommon},
+		{V: 'c', T: SesDelete},
+		{V: 'd', T: SesAdd},
+	}))
+}
+
+func TestComputeConcurrently(t *testing.T) {
+	var wg sync.WaitGroup
+	results := make([]*Result, 16)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = Compute("acbdeacbed", "acebdabbabed")
+		}(i)
+	}
+	wg.Wait()
+	for _, r := range results {
+		assert(t, r.Ed == 6)
+		assert(t, string(r.Lcs) == "acbdabed")
+	}
+}
+
+func TestComputeAll(t *testing.T) {
+	pairs := [][2]string{
+		{"abc", "abd"},
+		{"acbdeacbed", "acebdabbabed"},
+		{"", "abc"},
+		{"same", "same"},
+		{"kitten", "sitting"},
+	}
+	for _, workers := range []int{0, 1, 3, 10} {
+		results := ComputeAll(pairs, workers)
+		assert(t, len(results) == len(pairs))
+		for i, pair := range pairs {
+			r, _ := Compute(pair[0], pair[1])
+			assert(t, results[i].Ed == r.Ed)
+			assert(t, string(results[i].Lcs) == string(r.Lcs))
+			assert(t, equalsSesElemOfArray(results[i].Ses, r.Ses))
+		}
+	}
+	assert(t, len(ComputeAll(nil, 4)) == 0)
+}
@@ -0,0 +1,126 @@
+package gonp
+
+import (
+	"html"
+	"strings"
+	"unicode/utf8"
+)
+
+// sideBySideRow is a row of side-by-side view. left or right is nil when the line is absent on the side
+type sideBySideRow struct {
+	left, right *SesElemOf[string]
+}
+
+// sideBySideRows aligns common lines across both sides.
+// Deleted and added lines between common lines are placed on the same rows
+func sideBySideRows(ses []SesElemOf[string]) []sideBySideRow {
+	rows := make([]sideBySideRow, 0, len(ses))
+	i := 0
+	for i < len(ses) {
+		if ses[i].T == SesCommon {
+			rows = append(rows, sideBySideRow{left: &ses[i], right: &ses[i]})
+			i++
+			continue
+		}
+		dels, adds := make([]*SesElemOf[string], 0), make([]*SesElemOf[string], 0)
+		for ; i < len(ses) && ses[i].T != SesCommon; i++ {
+			if ses[i].T == SesDelete {
+				dels = append(dels, &ses[i])
+			} else {
+				adds = append(adds, &ses[i])
+			}
+		}
+		for j := 0; j < max(len(dels), len(adds)); j++ {
+			row := sideBySideRow{}
+			if j < len(dels) {
+				row.left = dels[j]
+			}
+			if j < len(adds) {
+				row.right = adds[j]
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// HTMLSideBySide returns two-column HTML table showing a on the left and b on the right.
+// Deleted and added lines are marked with class "delete" and "add"
+func (diff *LineDiff) HTMLSideBySide() string {
+	var buf strings.Builder
+	buf.WriteString("<table class=\"diff\">\n")
+	for _, row := range sideBySideRows(diff.Ses()) {
+		buf.WriteString("<tr>")
+		writeHTMLCell(&buf, row.left)
+		writeHTMLCell(&buf, row.right)
+		buf.WriteString("</tr>\n")
+	}
+	buf.WriteString("</table>\n")
+	return buf.String()
+}
+
+func writeHTMLCell(buf *strings.Builder, e *SesElemOf[string]) {
+	if e == nil {
+		buf.WriteString("<td></td>")
+		return
+	}
+	switch e.T {
+	case SesDelete:
+		buf.WriteString("<td class=\"delete\">")
+	case SesAdd:
+		buf.WriteString("<td class=\"add\">")
+	default:
+		buf.WriteString("<td>")
+	}
+	buf.WriteString(html.EscapeString(trimLine(e.V)))
+	buf.WriteString("</td>")
+}
+
+// SideBySide returns plain text two-column view like `diff -y`.
+// Lines are truncated to width characters. The gutter between columns is
+// "|" for changed lines, "<" for deleted lines and ">" for added lines.
+// Negative width is treated as 0, which shows only the gutter
+func (diff *LineDiff) SideBySide(width int) string {
+	width = max(width, 0)
+	var buf strings.Builder
+	for _, row := range sideBySideRows(diff.Ses()) {
+		left, right := "", ""
+		if row.left != nil {
+			left = truncateRunes(trimLine(row.left.V), width)
+		}
+		if row.right != nil {
+			right = truncateRunes(trimLine(row.right.V), width)
+		}
+		gutter := " "
+		switch {
+		case row.left == nil:
+			gutter = ">"
+		case row.right == nil:
+			gutter = "<"
+		case row.left.T != SesCommon:
+			gutter = "|"
+		}
+		buf.WriteString(left)
+		buf.WriteString(strings.Repeat(" ", width-utf8.RuneCountInString(left)))
+		buf.WriteString(" " + gutter)
+		if right != "" {
+			buf.WriteString(" " + right)
+		}
+		buf.WriteString("\n")
+	}
+	return buf.String()
+}
+
+func truncateRunes(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	n := 0
+	for i := range s {
+		if n == width {
+			return s[:i]
+		}
+		n++
+	}
+	return s
+}
@@ -0,0 +1,37 @@
+package gonp
+
+import (
+	"testing"
+)
+
+func TestHTMLSideBySide(t *testing.T) {
+	diff := NewLines("a\n<b>\nc\n", "a\nB & b\nx\nc\n")
+	diff.Compose()
+	expected := `<table class="diff">
+<tr><td>a</td><td>a</td></tr>
+<tr><td class="delete">&lt;b&gt;</td><td class="add">B &amp; b</td></tr>
+<tr><td></td><td class="add">x</td></tr>
+<tr><td>c</td><td>c</td></tr>
+</table>
+`
+	assert(t, diff.HTMLSideBySide() == expected)
+}
+
+func TestSideBySide(t *testing.T) {
+	diff := NewLines("a\nbbbbbb\nc\nd\n", "a\nB\nc\ne\nf\n")
+	diff.Compose()
+	expected := "a       a\n" +
+		"bbbbb | B\n" +
+		"c       c\n" +
+		"d     | e\n" +
+		"      > f\n"
+	assert(t, diff.SideBySide(5) == expected)
+
+	diff = NewLines("a\nb\n", "a\n")
+	assert(t, diff.SideBySide(3) == "a     a\nb   <\n")
+
+	// negative width is the same as 0
+	diff = NewLines("a\nb\n", "a\nc\n")
+	assert(t, diff.SideBySide(-1) == "  \n |\n")
+	assert(t, diff.SideBySide(-1) == diff.SideBySide(0))
+}
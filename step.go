@@ -0,0 +1,63 @@
+package gonp
+
+// Step advances composing diff between a and b by an iteration of p in O(NP) algorithm,
+// and reports whether composing is done. SES and LCS are recorded when it is done,
+// so a server can interleave many diffs by calling Step of each in turn.
+// Common prefix and suffix are trimmed before the iterations as Compose does.
+// When anchors, LinearSpace, Fast, AlignSuffix, IgnoreRunes, IgnoreWhitespace or RecordFrontiers
+// are enabled, Step composes at once.
+// Calling Compose or Reset abandons the iterations done so far
+func (diff *DiffOf[T]) Step() bool {
+	if diff.composed {
+		return true
+	}
+	if diff.anchors != nil || diff.linearSpace || diff.fast || diff.alignSuffix || diff.ignore != nil || diff.blank != nil || diff.recordFp {
+		diff.Compose()
+		return true
+	}
+	if !diff.stepping {
+		diff.resetResult()
+		diff.beginStep()
+		diff.stepping, diff.stepP = true, 0
+	}
+	sub := diff.stepSub
+	p := diff.stepP
+	diff.stepP++
+	sub.beginIteration(p)
+	if sub.exceedsMaxEd(p) {
+		diff.ed = -1
+		diff.stepping = false
+		diff.recordResult(nil, nil)
+		return true
+	}
+	if !sub.searchIteration(p) {
+		return false
+	}
+	diff.stepping = false
+	diff.ed = sub.ed
+	epc := sub.endSearch()
+	if sub != diff {
+		epc = diff.shiftPath(epc, diff.stepPrefix, diff.stepSuffix)
+	}
+	diff.recordResult(nil, epc)
+	return true
+}
+
+// beginStep prepares to search between common prefix and suffix of a and b by Step
+// as Compose does, or the whole edit graph when a or b is empty
+func (diff *DiffOf[T]) beginStep() {
+	diff.stepSub, diff.stepPrefix, diff.stepSuffix = diff, 0, 0
+	if diff.m > 0 && diff.n > 0 {
+		prefix, suffix := diff.commonAffixes()
+		if prefix > 0 || suffix > 0 {
+			diff.stepSub = diff.subDiff(prefix, diff.m-suffix, prefix, diff.n-suffix)
+			diff.stepSub.onlyEd = diff.onlyEd
+			diff.stepSub.maxEd = diff.maxEd
+			if !diff.onlyEd {
+				diff.runIntoSuffix(diff.stepSub, prefix, prefix, suffix)
+			}
+			diff.stepPrefix, diff.stepSuffix = prefix, suffix
+		}
+	}
+	diff.stepSub.beginSearch()
+}
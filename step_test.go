@@ -0,0 +1,80 @@
+package gonp
+
+import (
+	"testing"
+)
+
+func TestDiffStep(t *testing.T) {
+	pairs := [][2]string{
+		{"abc", "abd"},
+		{"abcdef", "dacfea"},
+		{"acbdeacbed", "acebdabbabed"},
+		{"", "abc"},
+		{"abc", ""},
+		{"", ""},
+		{"same", "same"},
+	}
+	for _, pair := range pairs {
+		expected := New(pair[0], pair[1])
+		expected.Compose()
+
+		diff := New(pair[0], pair[1])
+		steps := 1
+		for !diff.Step() {
+			steps++
+		}
+		m, n := len([]rune(pair[0])), len([]rune(pair[1]))
+		// Step returns true on the iteration of p where ed = |m-n|+2p is found, counting p from 0,
+		// so it takes p+1 calls
+		assert(t, steps == (expected.Editdistance()-max(m-n, n-m))/2+1)
+		assert(t, diff.Step())
+		assert(t, diff.Editdistance() == expected.Editdistance())
+		assert(t, diff.LcsString() == expected.LcsString())
+		assert(t, equalsSesElemOfArray(diff.Ses(), expected.Ses()))
+	}
+}
+
+func TestDiffStepMaxEd(t *testing.T) {
+	diff := New("abcdef", "dacfea")
+	diff.MaxEd(3)
+	for !diff.Step() {
+	}
+	assert(t, diff.Editdistance() == -1)
+	assert(t, len(diff.Ses()) == 0)
+}
+
+func TestDiffStepAbandoned(t *testing.T) {
+	diff := New("acbdeacbed", "acebdabbabed")
+	assert(t, !diff.Step())
+	diff.Compose()
+	assert(t, diff.Step())
+	assert(t, diff.Editdistance() == 6)
+
+	diff = New("acbdeacbed", "acebdabbabed")
+	assert(t, !diff.Step())
+	diff.Reset("abc", "abd")
+	for !diff.Step() {
+	}
+	assert(t, diff.LcsString() == "ab")
+}
+
+func TestDiffStepAtOnce(t *testing.T) {
+	diff := New("acbdeacbed", "acebdabbabed")
+	diff.LinearSpace()
+	assert(t, diff.Step())
+	assert(t, diff.Editdistance() == 6)
+	assert(t, diff.LcsString() == "acbdabed")
+}
+
+func TestDiffStepRecordFrontiers(t *testing.T) {
+	for _, pair := range [][2]string{{"abc", "abc"}, {"acbdeacbed", "acebdabbabed"}} {
+		expected := New(pair[0], pair[1])
+		expected.RecordFrontiers()
+		diff := New(pair[0], pair[1])
+		diff.RecordFrontiers()
+		assert(t, diff.Step())
+		assert(t, len(diff.Frontiers()) == len(expected.Frontiers()))
+		assert(t, len(diff.Frontiers()) > 0)
+		assert(t, diff.SprintSes() == expected.SprintSes())
+	}
+}
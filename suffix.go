@@ -0,0 +1,68 @@
+package gonp
+
+import (
+	"context"
+)
+
+// AlignSuffix enables to compose SES favoring matches toward the end of a and b.
+// Both a and b are reversed, composed and the result is reversed back, so common
+// elements tend to be aligned with trailing content rather than leading content.
+// Edit distance and the length of LCS are the same, but SES may differ.
+// Path returns the end of each run of the same manipulaton type instead of snakes.
+// It is ignored when anchors are given
+func (diff *DiffOf[T]) AlignSuffix() {
+	diff.alignSuffix = true
+}
+
+// findSuffixAlignedPath returns the path composed for reversed a and b and flipped back
+func (diff *DiffOf[T]) findSuffixAlignedPath(ctx context.Context) ([]Point, error) {
+	rev := diff.subDiff(0, diff.m, 0, diff.n)
+	rev.a, rev.b = reversed(diff.a), reversed(diff.b)
+	if diff.hashA != nil {
+		rev.hashA, rev.hashB = reversed(diff.hashA), reversed(diff.hashB)
+	}
+	rev.onlyEd = diff.onlyEd
+	rev.maxEd = diff.maxEd
+	rev.linearSpace = diff.linearSpace
+	epc, err := rev.findPath(ctx)
+	diff.ed = rev.ed
+	if err != nil || epc == nil {
+		return nil, err
+	}
+
+	types := make([]SesType, 0, diff.m+diff.n)
+	rev.recordSeq(epc, func(e SesElemOf[T]) bool {
+		types = append(types, e.T)
+		return true
+	})
+	// walk the path forward and take the end of each run of the same manipulaton type
+	points := make([]Point, 0)
+	x, y := 0, 0
+	for i := len(types) - 1; i >= 0; i-- {
+		if types[i] != SesAdd {
+			x++
+		}
+		if types[i] != SesDelete {
+			y++
+		}
+		if i == 0 || types[i-1] != types[i] {
+			points = append(points, Point{X: x, Y: y})
+		}
+	}
+	if len(points) == 0 {
+		points = append(points, Point{})
+	}
+	flipped := make([]Point, len(points))
+	for i, p := range points {
+		flipped[len(points)-1-i] = p
+	}
+	return flipped, nil
+}
+
+func reversed[T any](s []T) []T {
+	r := make([]T, len(s))
+	for i, v := range s {
+		r[len(s)-1-i] = v
+	}
+	return r
+}
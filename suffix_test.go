@@ -0,0 +1,53 @@
+package gonp
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestAlignSuffix(t *testing.T) {
+	diff := New("ab", "abab")
+	assert(t, diff.SprintSes() == "  a\n  b\n+ a\n+ b\n")
+
+	diff = New("ab", "abab")
+	diff.AlignSuffix()
+	assert(t, diff.SprintSes() == "+ a\n+ b\n  a\n  b\n")
+	assert(t, diff.Editdistance() == 2)
+	assert(t, diff.LcsString() == "ab")
+	ses := diff.Ses()
+	assert(t, ses[2].AIndex == 0 && ses[2].BIndex == 2)
+
+	diff = New("abab", "ab")
+	diff.AlignSuffix()
+	assert(t, diff.SprintSes() == "- a\n- b\n  a\n  b\n")
+
+	diff = New("", "")
+	diff.AlignSuffix()
+	assert(t, diff.Editdistance() == 0 && len(diff.Ses()) == 0)
+}
+
+func TestAlignSuffixRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	random := func() string {
+		rs := make([]rune, rnd.Intn(20))
+		for i := range rs {
+			rs[i] = rune('a' + rnd.Intn(3))
+		}
+		return string(rs)
+	}
+	for i := 0; i < 500; i++ {
+		a, b := random(), random()
+		diff := New(a, b)
+		aligned := New(a, b)
+		aligned.AlignSuffix()
+		assert(t, aligned.Editdistance() == diff.Editdistance())
+		assert(t, len(aligned.Lcs()) == len(diff.Lcs()))
+		result, err := ApplySes(a, aligned.Ses())
+		assert(t, err == nil && result == b)
+
+		onlyEd := New(a, b)
+		onlyEd.AlignSuffix()
+		onlyEd.OnlyEd()
+		assert(t, onlyEd.Editdistance() == diff.Editdistance())
+	}
+}
@@ -0,0 +1,37 @@
+package gonp
+
+// Tokenizer splits a string into tokens compared by NewTokens.
+// Concatenating tokens should reconstruct the string so that SES can be printed faithfully
+type Tokenizer interface {
+	Tokenize(s string) []string
+}
+
+// TokenizerFunc is an adapter to use an ordinary function as Tokenizer
+type TokenizerFunc func(s string) []string
+
+// Tokenize calls f(s)
+func (f TokenizerFunc) Tokenize(s string) []string {
+	return f(s)
+}
+
+var (
+	// LineTokenizer splits a string into lines like NewLines
+	LineTokenizer Tokenizer = TokenizerFunc(splitLines)
+	// WordTokenizer splits a string into words like NewWords
+	WordTokenizer Tokenizer = TokenizerFunc(splitWords)
+	// GraphemeTokenizer splits a string into grapheme clusters like NewGraphemes
+	GraphemeTokenizer Tokenizer = TokenizerFunc(splitGraphemes)
+	// ParagraphTokenizer splits a string into paragraphs like NewParagraphs
+	ParagraphTokenizer Tokenizer = TokenizerFunc(splitParagraphs)
+	// CodeTokenizer splits a string into words like WordTokenizer,
+	// and also splits identifiers in camelCase and snake_case into their components
+	CodeTokenizer Tokenizer = TokenizerFunc(splitCode)
+)
+
+// NewTokens is initializer of DiffOf comparing a and b token by token split by t.
+// Tokens are compared by their hashes first as SetHash
+func NewTokens(a, b string, t Tokenizer) *DiffOf[string] {
+	diff := NewSlice(t.Tokenize(a), t.Tokenize(b), equalString)
+	diff.SetHash(hashString)
+	return diff
+}
@@ -0,0 +1,28 @@
+package gonp
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewTokens(t *testing.T) {
+	csv := TokenizerFunc(func(s string) []string {
+		return strings.SplitAfter(s, ",")
+	})
+	diff := NewTokens("a,b,c", "a,x,c", csv)
+	assert(t, equalsSesElemOfArray(diff.Ses(), []SesElemOf[string]{
+		{V: "a,", T: SesCommon},
+		{V: "b,", T: SesDelete},
+		{V: "x,", T: SesAdd},
+		{V: "c", T: SesCommon},
+	}))
+}
+
+func TestBuiltinTokenizers(t *testing.T) {
+	a, b := "hello world\nfoo\n", "hello gonp\nfoo\n"
+	assert(t, equalsSesElemOfArray(NewTokens(a, b, LineTokenizer).Ses(), NewLines(a, b).Ses()))
+	assert(t, equalsSesElemOfArray(NewTokens(a, b, WordTokenizer).Ses(), NewWords(a, b).Ses()))
+	assert(t, equalsSesElemOfArray(NewTokens(a, b, GraphemeTokenizer).Ses(), NewGraphemes(a, b).Ses()))
+	assert(t, len(LineTokenizer.Tokenize(a)) == 2)
+	assert(t, len(WordTokenizer.Tokenize(a)) == 6)
+}
@@ -0,0 +1,155 @@
+package gonp
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const noNewlineMarker = "\\ No newline at end of file\n"
+
+// lineHunk is a range of SES shown in a hunk of unified diff
+type lineHunk struct {
+	aStart, aLen int
+	bStart, bLen int
+	ses          []SesElemOf[string]
+}
+
+// lineHunks groups SES into hunks surrounded by context common lines.
+// Changes separated by less than or equal to 2*context common lines are merged into a hunk
+func (diff *LineDiff) lineHunks(context int) []lineHunk {
+	hunks := make([]lineHunk, 0)
+	diff.eachLineHunk(context, func(h lineHunk) error {
+		hunks = append(hunks, h)
+		return nil
+	})
+	return hunks
+}
+
+// eachLineHunk calls fn for each hunk of lineHunks as soon as it is formed until fn returns an error.
+// SES is read by EachSes, so only the hunk being formed and context common lines before it are kept
+func (diff *LineDiff) eachLineHunk(context int, fn func(lineHunk) error) error {
+	if context < 0 {
+		context = 0
+	}
+	var (
+		err   error
+		a, b  int
+		lead  []SesElemOf[string]
+		h     *lineHunk
+		trail int
+	)
+	// flush passes the hunk to fn with keep common lines at its end, which are counted in trail
+	flush := func(keep int) {
+		h.ses = h.ses[:len(h.ses)-trail+keep]
+		for _, e := range h.ses {
+			if e.T != SesAdd {
+				h.aLen++
+			}
+			if e.T != SesDelete {
+				h.bLen++
+			}
+		}
+		err = fn(*h)
+		h = nil
+	}
+	diff.EachSes(func(e SesElemOf[string]) bool {
+		switch {
+		case h == nil && e.T == SesCommon:
+			lead = append(lead, e)
+			if len(lead) > context {
+				lead = lead[1:]
+			}
+		case h == nil:
+			h = &lineHunk{aStart: a - len(lead) + 1, bStart: b - len(lead) + 1}
+			h.ses = append(append(make([]SesElemOf[string], 0, len(lead)+1), lead...), e)
+			lead, trail = lead[:0], 0
+		case e.T == SesCommon:
+			h.ses = append(h.ses, e)
+			trail++
+			if trail > 2*context {
+				// context common lines after the hunk are left to lead the next one
+				lead = append(lead, h.ses[len(h.ses)-context:]...)
+				flush(context)
+			}
+		default:
+			h.ses = append(h.ses, e)
+			trail = 0
+		}
+		if e.T != SesAdd {
+			a++
+		}
+		if e.T != SesDelete {
+			b++
+		}
+		return err == nil
+	})
+	if err == nil && h != nil {
+		flush(min(trail, context))
+	}
+	return err
+}
+
+// UnifiedDiff returns unified format diff between a and b.
+// context is the number of unchanged lines surrounding each change.
+// A line at the end of input without trailing "\n" is followed by "\ No newline at end of file"
+func (diff *LineDiff) UnifiedDiff(fromFile, toFile string, context int) string {
+	var buf bytes.Buffer
+	diff.fprintUnified(&buf, fromFile, toFile, context)
+	return buf.String()
+}
+
+// WriteUnified writes unified format diff between a and b to w like UnifiedDiff.
+// Each hunk is written as soon as it is formed without building the whole diff in memory.
+// It returns the first error writing to w
+func (diff *LineDiff) WriteUnified(w io.Writer, fromFile, toFile string, context int) error {
+	return diff.fprintUnified(w, fromFile, toFile, context)
+}
+
+func (diff *LineDiff) fprintUnified(w io.Writer, fromFile, toFile string, context int) error {
+	headed := false
+	return diff.eachLineHunk(context, func(h lineHunk) error {
+		if !headed {
+			if _, err := fmt.Fprintf(w, "--- %s\n+++ %s\n", fromFile, toFile); err != nil {
+				return err
+			}
+			headed = true
+		}
+		return writeUnifiedHunk(w, h)
+	})
+}
+
+func writeUnifiedHunk(w io.Writer, h lineHunk) error {
+	if _, err := fmt.Fprintf(w, "@@ -%s +%s @@\n", unifiedRange(h.aStart, h.aLen), unifiedRange(h.bStart, h.bLen)); err != nil {
+		return err
+	}
+	for _, e := range h.ses {
+		var err error
+		switch e.T {
+		case SesDelete:
+			_, err = fmt.Fprintf(w, "-%s", terminateLine(e.V))
+		case SesAdd:
+			_, err = fmt.Fprintf(w, "+%s", terminateLine(e.V))
+		case SesCommon:
+			_, err = fmt.Fprintf(w, " %s", terminateLine(e.V))
+		}
+		if err == nil && !strings.HasSuffix(e.V, "\n") {
+			_, err = fmt.Fprint(w, noNewlineMarker)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func unifiedRange(start, length int) string {
+	if length == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	if length == 0 {
+		start--
+	}
+	return fmt.Sprintf("%d,%d", start, length)
+}
@@ -0,0 +1,125 @@
+package gonp
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	diff := NewLines("a\nb\nc\nd\ne\nf\ng\nh\n", "a\nB\nc\nd\ne\nf\ng\nH\n")
+	diff.Compose()
+	expected := `--- a.txt
++++ b.txt
+@@ -1,3 +1,3 @@
+ a
+-b
++B
+ c
+@@ -7,2 +7,2 @@
+ g
+-h
++H
+`
+	assert(t, diff.UnifiedDiff("a.txt", "b.txt", 1) == expected)
+}
+
+func TestUnifiedDiffMergeHunks(t *testing.T) {
+	diff := NewLines("a\nb\nc\nd\ne\n", "A\nb\nc\nd\nE\n")
+	diff.Compose()
+	expected := `--- a.txt
++++ b.txt
+@@ -1,5 +1,5 @@
+-a
++A
+ b
+ c
+ d
+-e
++E
+`
+	assert(t, diff.UnifiedDiff("a.txt", "b.txt", 2) == expected)
+}
+
+func TestUnifiedDiffEmptyRange(t *testing.T) {
+	diff := NewLines("a\nb\n", "a\nx\nb\n")
+	diff.Compose()
+	expected := `--- a.txt
++++ b.txt
+@@ -1,0 +2 @@
++x
+`
+	assert(t, diff.UnifiedDiff("a.txt", "b.txt", 0) == expected)
+}
+
+func TestUnifiedDiffNoChange(t *testing.T) {
+	diff := NewLines("a\nb\n", "a\nb\n")
+	diff.Compose()
+	assert(t, diff.UnifiedDiff("a.txt", "b.txt", 3) == "")
+}
+
+func TestUnifiedDiffNoNewlineAtEnd(t *testing.T) {
+	diff := NewLines("a\nb\nc", "a\nb\nc\n")
+	expected := `--- a.txt
++++ b.txt
+@@ -3 +3 @@
+-c
+\ No newline at end of file
++c
+`
+	assert(t, diff.UnifiedDiff("a.txt", "b.txt", 0) == expected)
+
+	diff = NewLines("a\nb\n", "a\nx")
+	expected = `--- a.txt
++++ b.txt
+@@ -1,2 +1,2 @@
+ a
+-b
++x
+\ No newline at end of file
+`
+	assert(t, diff.UnifiedDiff("a.txt", "b.txt", 3) == expected)
+
+	diff = NewLines("x\na", "y\na")
+	expected = `--- a.txt
++++ b.txt
+@@ -1,2 +1,2 @@
+-x
++y
+ a
+\ No newline at end of file
+`
+	assert(t, diff.UnifiedDiff("a.txt", "b.txt", 3) == expected)
+}
+
+func TestWriteUnified(t *testing.T) {
+	pairs := [][2]string{
+		{"a\nb\nc\nd\ne\nf\ng\nh\n", "a\nB\nc\nd\ne\nf\ng\nH\n"},
+		{"a\nb", "a\nc"},
+		{"", "a\n"},
+		{"a\n", "a\n"},
+	}
+	for _, pair := range pairs {
+		diff := NewLines(pair[0], pair[1])
+		var buf bytes.Buffer
+		assert(t, diff.WriteUnified(&buf, "a.txt", "b.txt", 1) == nil)
+		assert(t, buf.String() == diff.UnifiedDiff("a.txt", "b.txt", 1))
+	}
+
+	diff := NewLines("a\nb\nc\nd\ne\nf\ng\nh\n", "a\nB\nc\nd\ne\nf\ng\nH\n")
+	unified := diff.UnifiedDiff("a.txt", "b.txt", 1)
+	// fail while writing the second hunk
+	second := strings.Index(unified, "@@ -7")
+	w := &errWriter{n: second + 3}
+	assert(t, diff.WriteUnified(w, "a.txt", "b.txt", 1) == io.ErrShortWrite)
+	assert(t, w.buf.String() == unified[:second])
+	assert(t, diff.WriteUnified(&errWriter{}, "a.txt", "b.txt", 1) == io.ErrShortWrite)
+
+	// hunks are formed from EachSes without recording SES
+	diff = NewLines("a\nb\nc\nd\ne\nf\ng\nh\n", "a\nB\nc\nd\ne\nf\ng\nH\n")
+	var buf bytes.Buffer
+	assert(t, diff.WriteUnified(&buf, "a.txt", "b.txt", 1) == nil)
+	assert(t, buf.String() == unified)
+	assert(t, !diff.composed && len(diff.ses) == 0)
+}
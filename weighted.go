@@ -0,0 +1,73 @@
+package gonp
+
+// WeightedSes returns SES between a and b minimizing the total cost, where deleting or adding
+// an element costs 1 and substituting y in b for x in a costs cost(x, y).
+// A substitution is represented by a deletion followed by an addition, and it is
+// preferred to other alignments as long as the cost is less than 2.
+// Equal elements by eq cost 0 and cost is not called for them.
+// Since the O(NP) algorithm assumes unit costs, SES is calculated separately
+// by dynamic programming in O(MN) time and space, and it does not change the result of Compose
+func (diff *DiffOf[T]) WeightedSes(cost func(x, y T) float64) []SesElemOf[T] {
+	a, b := diff.sequences()
+	m, n := len(a), len(b)
+	d := make([][]float64, m+1)
+	for i := range d {
+		d[i] = make([]float64, n+1)
+		d[i][0] = float64(i)
+	}
+	for j := 0; j <= n; j++ {
+		d[0][j] = float64(j)
+	}
+	for i := 1; i <= m; i++ {
+		for j := 1; j <= n; j++ {
+			c := minFloat(d[i-1][j], d[i][j-1]) + 1
+			if diff.eq(a[i-1], b[j-1]) {
+				c = minFloat(c, d[i-1][j-1])
+			} else {
+				c = minFloat(c, d[i-1][j-1]+cost(a[i-1], b[j-1]))
+			}
+			d[i][j] = c
+		}
+	}
+
+	// trace back from the end preferring common elements and substitutions
+	reversed := make([]SesElemOf[T], 0, m+n)
+	i, j := m, n
+	for i > 0 || j > 0 {
+		if i > 0 && j > 0 {
+			if diff.eq(a[i-1], b[j-1]) {
+				if d[i][j] == d[i-1][j-1] {
+					reversed = append(reversed, SesElemOf[T]{V: a[i-1], T: SesCommon, AIndex: i - 1, BIndex: j - 1})
+					i, j = i-1, j-1
+					continue
+				}
+			} else if d[i][j] == d[i-1][j-1]+cost(a[i-1], b[j-1]) {
+				reversed = append(reversed,
+					SesElemOf[T]{V: b[j-1], T: SesAdd, AIndex: -1, BIndex: j - 1},
+					SesElemOf[T]{V: a[i-1], T: SesDelete, AIndex: i - 1, BIndex: -1})
+				i, j = i-1, j-1
+				continue
+			}
+		}
+		if i > 0 && d[i][j] == d[i-1][j]+1 {
+			reversed = append(reversed, SesElemOf[T]{V: a[i-1], T: SesDelete, AIndex: i - 1, BIndex: -1})
+			i--
+		} else {
+			reversed = append(reversed, SesElemOf[T]{V: b[j-1], T: SesAdd, AIndex: -1, BIndex: j - 1})
+			j--
+		}
+	}
+
+	ses := make([]SesElemOf[T], len(reversed))
+	for k, e := range reversed {
+		ses[len(reversed)-1-k] = e
+	}
+	return ses
+}
+
+func minFloat(x, y float64) float64 {
+	if x > y {
+		return y
+	}
+	return x
+}
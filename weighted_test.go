@@ -0,0 +1,50 @@
+package gonp
+
+import (
+	"testing"
+)
+
+func TestWeightedSes(t *testing.T) {
+	diff := New("ax", "xb")
+	assert(t, equalsSesElemOfArray(diff.Ses(), []SesElem{
+		{V: 'a', T: SesDelete},
+		{V: 'x', T: SesCommon},
+		{V: 'b', T: SesAdd},
+	}))
+	cheap := func(x, y rune) float64 { return 0.5 }
+	assert(t, equalsSesElemOfArray(diff.WeightedSes(cheap), []SesElem{
+		{V: 'a', T: SesDelete},
+		{V: 'x', T: SesAdd},
+		{V: 'x', T: SesDelete},
+		{V: 'b', T: SesAdd},
+	}))
+	// substitutions costing 2 or more are never preferred to the shortest edit script
+	expensive := func(x, y rune) float64 { return 2 }
+	ses := diff.WeightedSes(expensive)
+	assert(t, len(ses) == 3 && ses[1].T == SesCommon)
+}
+
+func TestWeightedSesKeyboard(t *testing.T) {
+	adjacent := map[[2]rune]bool{{'a', 's'}: true, {'s', 'a'}: true, {'o', 'p'}: true, {'p', 'o'}: true}
+	cost := func(x, y rune) float64 {
+		if adjacent[[2]rune{x, y}] {
+			return 0.5
+		}
+		return 1.5
+	}
+	diff := New("cst", "cat")
+	ses := diff.WeightedSes(cost)
+	b, err := ApplySes("cst", ses)
+	assert(t, err == nil && b == "cat")
+	assert(t, equalsSesElemOfArray(ses, []SesElem{
+		{V: 'c', T: SesCommon},
+		{V: 's', T: SesDelete},
+		{V: 'a', T: SesAdd},
+		{V: 't', T: SesCommon},
+	}))
+
+	for _, pair := range [][2]string{{"", "abc"}, {"abc", ""}, {"stop", "post"}, {"acbdeacbed", "acebdabbabed"}} {
+		result, err := ApplySes(pair[0], New(pair[0], pair[1]).WeightedSes(cost))
+		assert(t, err == nil && result == pair[1])
+	}
+}
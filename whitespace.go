@@ -0,0 +1,99 @@
+package gonp
+
+import (
+	"context"
+	"unicode"
+)
+
+// isBlank reports whether r is whitespace in a line, whose runs are collapsed by IgnoreWhitespace
+func isBlank(r rune) bool {
+	return r != '\n' && unicode.IsSpace(r)
+}
+
+// collapsedIndices returns elements of s with each run of blank elements collapsed into its first one,
+// and the indices of them in s
+func (diff *DiffOf[T]) collapsedIndices(s []T) ([]T, []int) {
+	collapsed := make([]T, 0, len(s))
+	indices := make([]int, 0, len(s))
+	for i, v := range s {
+		if i > 0 && diff.blank(v) && diff.blank(s[i-1]) {
+			continue
+		}
+		collapsed = append(collapsed, v)
+		indices = append(indices, i)
+	}
+	return collapsed, indices
+}
+
+// runLen returns the length of the run of elements of s collapsed into s[i]
+func (diff *DiffOf[T]) runLen(s []T, i int) int {
+	if !diff.blank(s[i]) {
+		return 1
+	}
+	j := i + 1
+	for j < len(s) && diff.blank(s[j]) {
+		j++
+	}
+	return j - i
+}
+
+// findCollapsedPath searches the path between a and b with runs of blank elements collapsed,
+// and returns it with the runs expanded in reverse order. Identical elements at the beginning of
+// matched runs are common, and the rest of them are deleted and added without counting edit distance
+func (diff *DiffOf[T]) findCollapsedPath(ctx context.Context) ([]Point, error) {
+	sub := new(DiffOf[T])
+	var ia, ib []int
+	sub.a, ia = diff.collapsedIndices(diff.a)
+	sub.b, ib = diff.collapsedIndices(diff.b)
+	sub.m, sub.n = len(ia), len(ib)
+	sub.eq = diff.eq
+	sub.progress = diff.progress
+	sub.work = diff.work
+	sub.linearSpace = diff.linearSpace
+	sub.fast = diff.fast
+	sub.alignSuffix = diff.alignSuffix
+	sub.onlyEd = diff.onlyEd
+	sub.limit = -1
+	sub.maxEd = diff.maxEd
+	epc, err := sub.findPath(ctx)
+	diff.ed = sub.ed
+	if err != nil || epc == nil {
+		return nil, err
+	}
+
+	points := []Point{{X: 0, Y: 0}}
+	x, y := 0, 0
+	step := func(dx, dy int) {
+		x, y = x+dx, y+dy
+		points = append(points, Point{X: x, Y: y})
+	}
+	sub.recordSeq(epc, func(e SesElemOf[T]) bool {
+		switch e.T {
+		case SesCommon:
+			x1, y1 := x+diff.runLen(diff.a, ia[e.AIndex]), y+diff.runLen(diff.b, ib[e.BIndex])
+			for x < x1 && y < y1 && diff.same(diff.a[x], diff.b[y]) {
+				step(1, 1)
+			}
+			for x < x1 {
+				step(1, 0)
+			}
+			for y < y1 {
+				step(0, 1)
+			}
+		case SesDelete:
+			for n := diff.runLen(diff.a, ia[e.AIndex]); n > 0; n-- {
+				step(1, 0)
+			}
+		case SesAdd:
+			for n := diff.runLen(diff.b, ib[e.BIndex]); n > 0; n-- {
+				step(0, 1)
+			}
+		}
+		return true
+	})
+
+	for i, j := 0, len(points)-1; i < j; i, j = i+1, j-1 {
+		points[i], points[j] = points[j], points[i]
+	}
+	return points, nil
+}
@@ -0,0 +1,45 @@
+package gonp
+
+import (
+	"unicode"
+)
+
+// NewWords is initializer of DiffOf comparing a and b word by word.
+// Words are runs of letters and digits, runs of whitespace are single tokens
+// and any other character is a token by itself, so concatenating tokens reconstructs input
+func NewWords(a, b string) *DiffOf[string] {
+	return NewTokens(a, b, WordTokenizer)
+}
+
+const (
+	wordClassOther = iota
+	wordClassWord
+	wordClassSpace
+)
+
+func wordClass(r rune) int {
+	switch {
+	case unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r):
+		return wordClassWord
+	case unicode.IsSpace(r):
+		return wordClassSpace
+	}
+	return wordClassOther
+}
+
+func splitWords(s string) []string {
+	words := make([]string, 0)
+	start, class := 0, -1
+	for i, r := range s {
+		c := wordClass(r)
+		if i > start && (c != class || c == wordClassOther) {
+			words = append(words, s[start:i])
+			start = i
+		}
+		class = c
+	}
+	if start < len(s) {
+		words = append(words, s[start:])
+	}
+	return words
+}
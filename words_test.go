@@ -0,0 +1,41 @@
+package gonp
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSplitWords(t *testing.T) {
+	words := splitWords("Hello,  wörld 42!\tok")
+	expected := []string{"Hello", ",", "  ", "wörld", " ", "42", "!", "\t", "ok"}
+	assert(t, len(words) == len(expected))
+	for i := range expected {
+		assert(t, words[i] == expected[i])
+	}
+	assert(t, len(splitWords("")) == 0)
+}
+
+func TestDiffWords(t *testing.T) {
+	diff := NewWords("the quick brown fox", "the slow brown fox")
+	diff.Compose()
+	sesExpected := []SesElemOf[string]{
+		{V: "the", T: SesCommon},
+		{V: " ", T: SesCommon},
+		{V: "quick", T: SesDelete},
+		{V: "slow", T: SesAdd},
+		{V: " ", T: SesCommon},
+		{V: "brown", T: SesCommon},
+		{V: " ", T: SesCommon},
+		{V: "fox", T: SesCommon},
+	}
+	assert(t, diff.Editdistance() == 2)
+	assert(t, equalsSesElemOfArray(diff.Ses(), sesExpected))
+
+	var b strings.Builder
+	for _, e := range diff.Ses() {
+		if e.T != SesDelete {
+			b.WriteString(e.V)
+		}
+	}
+	assert(t, b.String() == "the slow brown fox")
+}
@@ -0,0 +1,18 @@
+package gonp
+
+// WorkStats is the amount of work done by the last Compose, which is useful for tuning performance.
+// Iterations is the number of iterations of p in O(NP) algorithm summed over every part searched,
+// and SnakeSteps is the number of diagonal moves along snakes on the edit graph
+type WorkStats struct {
+	Iterations int
+	SnakeSteps int
+}
+
+// Work returns the amount of work done by the last Compose.
+// It is zero before Compose is called, or when the path is found without searching
+func (diff *DiffOf[T]) Work() WorkStats {
+	if diff.work == nil {
+		return WorkStats{}
+	}
+	return *diff.work
+}
@@ -0,0 +1,33 @@
+package gonp
+
+import (
+	"testing"
+)
+
+func TestDiffWork(t *testing.T) {
+	diff := New("acbdeacbed", "acebdabbabed")
+	assert(t, diff.Work() == WorkStats{})
+	diff.Compose()
+	// ed = delta + 2p, and p is iterated from 0
+	assert(t, diff.Editdistance() == 6)
+	assert(t, diff.Work() == WorkStats{Iterations: 3, SnakeSteps: 5})
+
+	diff.Compose()
+	assert(t, diff.Work() == WorkStats{Iterations: 3, SnakeSteps: 5})
+
+	diff = New("xabc", "yabc")
+	diff.Compose()
+	assert(t, diff.Work() == WorkStats{Iterations: 2, SnakeSteps: 0})
+	diff = New("xabc", "yabc")
+	diff.LinearSpace()
+	diff.Compose()
+	assert(t, diff.Work() == WorkStats{Iterations: 2, SnakeSteps: 0})
+}
+
+func TestDiffWorkWithoutSearching(t *testing.T) {
+	for _, pair := range [][2]string{{"abc", "abc"}, {"", "abc"}, {"abc", ""}} {
+		diff := New(pair[0], pair[1])
+		diff.Compose()
+		assert(t, diff.Work() == WorkStats{})
+	}
+}